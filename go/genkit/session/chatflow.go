@@ -0,0 +1,91 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/core"
+	"github.com/firebase/genkit/go/genkit"
+)
+
+// ChatFunc is the handler signature for DefineChatFlow. history is the
+// session's prior messages, already trimmed per the configured
+// Trimmer, ready to pass straight to ai.Prompt.Execute via
+// ai.WithMessages. newTurns are the messages DefineChatFlow should
+// record back to the session once fn returns successfully (typically
+// the user's new message followed by reply.Message()); pass nil to
+// leave the session untouched.
+type ChatFunc[In any] func(ctx context.Context, input In, history []*ai.Message) (reply *ai.ModelResponse, newTurns []*ai.Message, err error)
+
+// chatConfig holds the options accumulated from DefineChatFlow's
+// ChatOption arguments.
+type chatConfig struct {
+	trimmer Trimmer
+}
+
+// ChatOption configures DefineChatFlow.
+type ChatOption func(*chatConfig)
+
+// WithTrimmer sets the Trimmer DefineChatFlow runs over a session's
+// history before handing it to the ChatFunc. Without one, history is
+// passed through untrimmed.
+func WithTrimmer(t Trimmer) ChatOption {
+	return func(c *chatConfig) { c.trimmer = t }
+}
+
+// DefineChatFlow registers a multi-turn chat flow on g the same way
+// genkit.DefineFlow does, except fn is additionally handed the
+// session's message history (loaded and trimmed automatically from the
+// context Middleware attaches to the request) instead of every flow
+// re-implementing greetingWithHistory's pattern of threading
+// previousOrder by hand. The flow's output is reply.Text().
+func DefineChatFlow[In any](g *genkit.Genkit, name string, fn ChatFunc[In], opts ...ChatOption) *core.Flow[In, string, struct{}] {
+	cfg := &chatConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return genkit.DefineFlow(g, name, func(ctx context.Context, input In) (string, error) {
+		history, err := Load(ctx)
+		if err != nil {
+			return "", fmt.Errorf("session: loading history for chat flow %q: %w", name, err)
+		}
+		if cfg.trimmer != nil {
+			history, err = cfg.trimmer.Trim(ctx, history)
+			if err != nil {
+				return "", fmt.Errorf("session: trimming history for chat flow %q: %w", name, err)
+			}
+		}
+
+		reply, newTurns, err := fn(ctx, input, history)
+		if err != nil {
+			return "", err
+		}
+		if reply == nil {
+			return "", fmt.Errorf("session: chat flow %q returned a nil reply", name)
+		}
+		if len(newTurns) > 0 {
+			if err := Append(ctx, newTurns...); err != nil {
+				return "", fmt.Errorf("session: recording turns for chat flow %q: %w", name, err)
+			}
+		}
+		return reply.Text(), nil
+	})
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"maps"
+	"sync"
+
+	"github.com/firebase/genkit/go/ai"
+)
+
+// InMemoryStore implements Store in process memory. It is the default
+// choice for local development and tests; sessions do not survive a
+// restart and are not shared across replicas.
+type InMemoryStore struct {
+	mu       sync.Mutex
+	sessions map[string]*inMemorySession
+}
+
+type inMemorySession struct {
+	history []*ai.Message
+	state   map[string]any
+}
+
+// NewInMemoryStore returns an empty InMemoryStore.
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{sessions: map[string]*inMemorySession{}}
+}
+
+func (s *InMemoryStore) get(id string) *inMemorySession {
+	sess, ok := s.sessions[id]
+	if !ok {
+		sess = &inMemorySession{}
+		s.sessions[id] = sess
+	}
+	return sess
+}
+
+// Load implements Store.
+func (s *InMemoryStore) Load(_ context.Context, id string) ([]*ai.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]*ai.Message(nil), sess.history...), nil
+}
+
+// Append implements Store.
+func (s *InMemoryStore) Append(_ context.Context, id string, msgs ...*ai.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess := s.get(id)
+	sess.history = append(sess.history, msgs...)
+	return nil
+}
+
+// State implements Store.
+func (s *InMemoryStore) State(_ context.Context, id string) (map[string]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return maps.Clone(sess.state), nil
+}
+
+// SetState implements Store.
+func (s *InMemoryStore) SetState(_ context.Context, id string, state map[string]any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess := s.get(id)
+	sess.state = maps.Clone(state)
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// config holds the options accumulated from Middleware's Option
+// arguments.
+type config struct {
+	header     string
+	cookie     string
+	autoCreate bool
+}
+
+// Option configures Middleware.
+type Option func(*config)
+
+// WithHeader sets the request header Middleware reads the session ID
+// from. The default is "X-Session-Id".
+func WithHeader(name string) Option {
+	return func(c *config) { c.header = name }
+}
+
+// WithCookie sets the cookie name Middleware falls back to when header
+// is absent, and that a newly minted session ID is written back to. The
+// default is "genkit_session".
+func WithCookie(name string) Option {
+	return func(c *config) { c.cookie = name }
+}
+
+// WithAutoCreate controls whether Middleware mints a new session ID
+// (and sets it as a response cookie) for requests that carry none. It
+// defaults to true; pass false to instead let such requests through
+// with no session attached to their context.
+func WithAutoCreate(autoCreate bool) Option {
+	return func(c *config) { c.autoCreate = autoCreate }
+}
+
+// Middleware resolves a session ID for each request, from, in order,
+// the header set by WithHeader, the cookie set by WithCookie, or (when
+// WithAutoCreate is enabled, the default) a freshly generated one sent
+// back as a Set-Cookie. It attaches store and the resolved ID to the
+// request context via WithSession so flow handlers downstream can call
+// Load, Append, State, and SetState without threading a session ID
+// through every function signature.
+func Middleware(next http.Handler, store Store, opts ...Option) http.Handler {
+	cfg := &config{
+		header:     "X-Session-Id",
+		cookie:     "genkit_session",
+		autoCreate: true,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(cfg.header)
+		if id == "" {
+			if c, err := r.Cookie(cfg.cookie); err == nil {
+				id = c.Value
+			}
+		}
+		if id == "" {
+			if !cfg.autoCreate {
+				next.ServeHTTP(w, r)
+				return
+			}
+			id = newSessionID()
+			http.SetCookie(w, &http.Cookie{
+				Name:     cfg.cookie,
+				Value:    id,
+				Path:     "/",
+				HttpOnly: true,
+				Secure:   r.TLS != nil,
+				SameSite: http.SameSiteLaxMode,
+			})
+		}
+		next.ServeHTTP(w, r.WithContext(WithSession(r.Context(), store, id)))
+	})
+}
+
+// newSessionID generates a random, URL-safe session identifier.
+func newSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic("session: failed to generate session ID: " + err.Error())
+	}
+	return hex.EncodeToString(b)
+}
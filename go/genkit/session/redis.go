@@ -0,0 +1,110 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore implements Store on top of a Redis client, so sessions
+// survive restarts and are shared across replicas. History and state
+// are stored as JSON under "<prefix><id>:history" and
+// "<prefix><id>:state".
+type RedisStore struct {
+	client redis.Cmdable
+	prefix string
+	ttl    time.Duration
+}
+
+// NewRedisStore returns a Store backed by client. Each of a session's
+// two keys expires ttl after it was last written (history by Append,
+// state by SetState); reads do not extend it, and writing one key does
+// not extend the other. Pass 0 to keep sessions indefinitely. keyPrefix
+// defaults to "genkit:session:".
+func NewRedisStore(client redis.Cmdable, keyPrefix string, ttl time.Duration) *RedisStore {
+	if keyPrefix == "" {
+		keyPrefix = "genkit:session:"
+	}
+	return &RedisStore{client: client, prefix: keyPrefix, ttl: ttl}
+}
+
+func (s *RedisStore) historyKey(id string) string { return s.prefix + id + ":history" }
+func (s *RedisStore) stateKey(id string) string   { return s.prefix + id + ":state" }
+
+// Load implements Store.
+func (s *RedisStore) Load(ctx context.Context, id string) ([]*ai.Message, error) {
+	raw, err := s.client.Get(ctx, s.historyKey(id)).Bytes()
+	if err == redis.Nil {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var msgs []*ai.Message
+	if err := json.Unmarshal(raw, &msgs); err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
+
+// Append implements Store. It does a read-modify-write of the whole
+// history, so two concurrent Appends for the same id can race and drop
+// one of the writes; callers that append to a single session from
+// multiple goroutines or replicas at once should serialize those calls
+// themselves.
+func (s *RedisStore) Append(ctx context.Context, id string, msgs ...*ai.Message) error {
+	existing, err := s.Load(ctx, id)
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+	existing = append(existing, msgs...)
+	raw, err := json.Marshal(existing)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, s.historyKey(id), raw, s.ttl).Err()
+}
+
+// State implements Store.
+func (s *RedisStore) State(ctx context.Context, id string) (map[string]any, error) {
+	raw, err := s.client.Get(ctx, s.stateKey(id)).Bytes()
+	if err == redis.Nil {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state map[string]any
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// SetState implements Store.
+func (s *RedisStore) SetState(ctx context.Context, id string, state map[string]any) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, s.stateKey(id), raw, s.ttl).Err()
+}
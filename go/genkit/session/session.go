@@ -0,0 +1,132 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package session persists multi-turn conversation history for Genkit
+// flows, so a flow like greetingWithHistory can load prior turns by
+// session ID instead of requiring every caller to pass the whole
+// history (or a hand-picked summary of it, like previousOrder) on every
+// request.
+package session
+
+import (
+	"context"
+	"errors"
+
+	"github.com/firebase/genkit/go/ai"
+)
+
+// ErrNotFound is returned by Store.Load and Store.State when id has no
+// recorded session.
+var ErrNotFound = errors.New("session: not found")
+
+// Store persists conversation history and arbitrary per-session state,
+// keyed by session ID.
+type Store interface {
+	// Load returns the message history recorded for id, or
+	// ErrNotFound if no session with that ID exists yet.
+	Load(ctx context.Context, id string) ([]*ai.Message, error)
+
+	// Append records msgs at the end of id's history, creating the
+	// session if it doesn't exist.
+	Append(ctx context.Context, id string, msgs ...*ai.Message) error
+
+	// State returns the arbitrary key/value state attached to id
+	// (e.g. CustomerName, PreviousOrder), or ErrNotFound.
+	State(ctx context.Context, id string) (map[string]any, error)
+
+	// SetState replaces the key/value state attached to id, creating
+	// the session if it doesn't exist.
+	SetState(ctx context.Context, id string, state map[string]any) error
+}
+
+// sessionContextKey is the context key under which the active store and
+// session ID are stashed by Middleware.
+type sessionContextKey struct{}
+
+type sessionContext struct {
+	store Store
+	id    string
+}
+
+// WithSession returns a copy of ctx carrying store and id, so Load and
+// Append (and a flow's own calls to store methods) don't need id
+// threaded through every function signature.
+func WithSession(ctx context.Context, store Store, id string) context.Context {
+	return context.WithValue(ctx, sessionContextKey{}, &sessionContext{store: store, id: id})
+}
+
+// fromContext retrieves the store/id pair attached by WithSession.
+func fromContext(ctx context.Context) (*sessionContext, bool) {
+	sc, ok := ctx.Value(sessionContextKey{}).(*sessionContext)
+	return sc, ok
+}
+
+// ID returns the session ID attached to ctx by Middleware, if any.
+func ID(ctx context.Context) (string, bool) {
+	sc, ok := fromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	return sc.id, true
+}
+
+// Load returns the message history for the session attached to ctx. It
+// returns an empty slice, not an error, if the session has no history
+// yet.
+func Load(ctx context.Context) ([]*ai.Message, error) {
+	sc, ok := fromContext(ctx)
+	if !ok {
+		return nil, errors.New("session: no session attached to context, did you install Middleware?")
+	}
+	msgs, err := sc.store.Load(ctx, sc.id)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return msgs, err
+}
+
+// Append records msgs at the end of the session attached to ctx.
+func Append(ctx context.Context, msgs ...*ai.Message) error {
+	sc, ok := fromContext(ctx)
+	if !ok {
+		return errors.New("session: no session attached to context, did you install Middleware?")
+	}
+	return sc.store.Append(ctx, sc.id, msgs...)
+}
+
+// State returns the key/value state for the session attached to ctx, or
+// an empty map if none has been set yet.
+func State(ctx context.Context) (map[string]any, error) {
+	sc, ok := fromContext(ctx)
+	if !ok {
+		return nil, errors.New("session: no session attached to context, did you install Middleware?")
+	}
+	state, err := sc.store.State(ctx, sc.id)
+	if errors.Is(err, ErrNotFound) || (err == nil && state == nil) {
+		return map[string]any{}, nil
+	}
+	return state, err
+}
+
+// SetState replaces the key/value state for the session attached to
+// ctx.
+func SetState(ctx context.Context, state map[string]any) error {
+	sc, ok := fromContext(ctx)
+	if !ok {
+		return errors.New("session: no session attached to context, did you install Middleware?")
+	}
+	return sc.store.SetState(ctx, sc.id, state)
+}
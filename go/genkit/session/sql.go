@@ -0,0 +1,141 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/firebase/genkit/go/ai"
+)
+
+// SQLStore implements Store on top of a database/sql.DB, for deployments
+// that already run a relational database and would rather not add Redis
+// as a second stateful dependency. History and state are stored as JSON
+// blobs in a single table, created by CreateTable with:
+//
+//	CREATE TABLE IF NOT EXISTS genkit_sessions (
+//	    id      TEXT PRIMARY KEY,
+//	    history TEXT NOT NULL DEFAULT '[]',
+//	    state   TEXT NOT NULL DEFAULT '{}'
+//	);
+//
+// The queries below use "$1"-style placeholders, and upsert uses
+// ON CONFLICT, which Postgres and SQLite both accept; MySQL users
+// should swap in "?" placeholders and ON DUPLICATE KEY UPDATE.
+type SQLStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLStore returns a Store backed by db. table defaults to
+// "genkit_sessions".
+func NewSQLStore(db *sql.DB, table string) *SQLStore {
+	if table == "" {
+		table = "genkit_sessions"
+	}
+	return &SQLStore{db: db, table: table}
+}
+
+// CreateTable creates s's backing table if it doesn't already exist.
+// It's a convenience for local development and tests; production
+// deployments will typically manage the schema via a migration tool
+// instead.
+func (s *SQLStore) CreateTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(
+		`CREATE TABLE IF NOT EXISTS %s (
+			id      TEXT PRIMARY KEY,
+			history TEXT NOT NULL DEFAULT '[]',
+			state   TEXT NOT NULL DEFAULT '{}'
+		)`, s.table))
+	return err
+}
+
+// Load implements Store.
+func (s *SQLStore) Load(ctx context.Context, id string) ([]*ai.Message, error) {
+	var raw string
+	err := s.db.QueryRowContext(ctx,
+		fmt.Sprintf(`SELECT history FROM %s WHERE id = $1`, s.table), id).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var msgs []*ai.Message
+	if err := json.Unmarshal([]byte(raw), &msgs); err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
+
+// Append implements Store. Like RedisStore.Append, it reads then writes
+// the whole history without a transaction, so concurrent Appends for
+// the same id can race; serialize calls for a given session if more
+// than one goroutine or replica might append to it at once.
+func (s *SQLStore) Append(ctx context.Context, id string, msgs ...*ai.Message) error {
+	existing, err := s.Load(ctx, id)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	existing = append(existing, msgs...)
+	raw, err := json.Marshal(existing)
+	if err != nil {
+		return err
+	}
+	return s.upsert(ctx, id, "history", string(raw))
+}
+
+// State implements Store.
+func (s *SQLStore) State(ctx context.Context, id string) (map[string]any, error) {
+	var raw string
+	err := s.db.QueryRowContext(ctx,
+		fmt.Sprintf(`SELECT state FROM %s WHERE id = $1`, s.table), id).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state map[string]any
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// SetState implements Store.
+func (s *SQLStore) SetState(ctx context.Context, id string, state map[string]any) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return s.upsert(ctx, id, "state", string(raw))
+}
+
+// upsert writes value into column for id, inserting a new row (with the
+// other column left at its default) if id has no session yet.
+func (s *SQLStore) upsert(ctx context.Context, id, column, value string) error {
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO %[1]s (id, %[2]s) VALUES ($1, $2)
+		 ON CONFLICT (id) DO UPDATE SET %[2]s = excluded.%[2]s`, s.table, column),
+		id, value)
+	return err
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/firebase/genkit/go/ai"
+)
+
+// Trimmer shrinks a conversation's history so long-running sessions
+// don't blow the model's context window. DefineChatFlow runs the
+// configured Trimmer over the loaded history before handing it to the
+// chat function.
+type Trimmer interface {
+	// Trim returns the (possibly shortened) history; the result may
+	// share history's backing array. It must not modify history in
+	// place.
+	Trim(ctx context.Context, history []*ai.Message) ([]*ai.Message, error)
+}
+
+// TrimmerFunc adapts a function to a Trimmer.
+type TrimmerFunc func(ctx context.Context, history []*ai.Message) ([]*ai.Message, error)
+
+// Trim implements Trimmer.
+func (f TrimmerFunc) Trim(ctx context.Context, history []*ai.Message) ([]*ai.Message, error) {
+	return f(ctx, history)
+}
+
+// SlidingWindow returns a Trimmer that keeps only the most recent turns
+// messages, dropping older ones. A "turn" here is a single message
+// (user or model); pass an even number to avoid splitting a
+// user/response pair.
+func SlidingWindow(turns int) Trimmer {
+	return TrimmerFunc(func(_ context.Context, history []*ai.Message) ([]*ai.Message, error) {
+		if turns <= 0 || len(history) <= turns {
+			return history, nil
+		}
+		return history[len(history)-turns:], nil
+	})
+}
+
+// TokenCounter estimates how many tokens msg will cost. Counting
+// exactly requires the target model's tokenizer; EstimateTokens is a
+// reasonable default when one isn't available.
+type TokenCounter func(msg *ai.Message) int
+
+// EstimateTokens approximates token count as one token per four
+// characters of text content, the same rule of thumb the OpenAI and
+// Gemini docs use for English text. It is deliberately rough: callers
+// with an exact tokenizer should supply their own TokenCounter.
+func EstimateTokens(msg *ai.Message) int {
+	n := 0
+	for _, p := range msg.Content {
+		n += len(p.Text) / 4
+	}
+	return n
+}
+
+// TokenBudget returns a Trimmer that drops the oldest messages until
+// the remaining history's estimated token count (per count, or
+// EstimateTokens if count is nil) fits within maxTokens.
+func TokenBudget(maxTokens int, count TokenCounter) Trimmer {
+	if count == nil {
+		count = EstimateTokens
+	}
+	return TrimmerFunc(func(_ context.Context, history []*ai.Message) ([]*ai.Message, error) {
+		total := 0
+		for _, m := range history {
+			total += count(m)
+		}
+		start := 0
+		for total > maxTokens && start < len(history) {
+			total -= count(history[start])
+			start++
+		}
+		return history[start:], nil
+	})
+}
+
+// Summarizer calls a model to compress the given messages into a
+// single summary message, used by Summarizing to collapse the part of
+// history it drops rather than discarding it outright.
+type Summarizer func(ctx context.Context, history []*ai.Message) (*ai.Message, error)
+
+// Summarizing returns a Trimmer that keeps the keepRecent most recent
+// messages verbatim and replaces everything older with a single model
+// message produced by summarize, so long conversations shrink instead
+// of losing earlier context entirely. It is a no-op until history grows
+// past keepRecent.
+func Summarizing(keepRecent int, summarize Summarizer) Trimmer {
+	return TrimmerFunc(func(ctx context.Context, history []*ai.Message) ([]*ai.Message, error) {
+		if keepRecent < 0 || len(history) <= keepRecent {
+			return history, nil
+		}
+		older, recent := history[:len(history)-keepRecent], history[len(history)-keepRecent:]
+		summary, err := summarize(ctx, older)
+		if err != nil {
+			return nil, fmt.Errorf("session: summarizing history: %w", err)
+		}
+		out := make([]*ai.Message, 0, 1+len(recent))
+		out = append(out, summary)
+		out = append(out, recent...)
+		return out, nil
+	})
+}
+
+// PromptSummarizer adapts an *ai.Prompt into a Summarizer, for apps
+// that already define their summarization prompt (model, instructions,
+// output format) the same way they define every other Genkit prompt via
+// genkit.DefinePrompt. toInput builds that prompt's input value from the
+// flattened transcript of the messages being dropped.
+func PromptSummarizer[In any](prompt *ai.Prompt, toInput func(transcript string) In) Summarizer {
+	return func(ctx context.Context, history []*ai.Message) (*ai.Message, error) {
+		var transcript string
+		for _, msg := range history {
+			transcript += fmt.Sprintf("%s: %s\n", msg.Role, msg.Text())
+		}
+		resp, err := prompt.Execute(ctx, ai.WithInput(toInput(transcript)))
+		if err != nil {
+			return nil, err
+		}
+		return ai.NewModelTextMessage("Summary of earlier conversation: " + resp.Text()), nil
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"net/http"
+)
+
+// APIKey is the record an APIKeyStore returns for a valid key.
+type APIKey struct {
+	OwnerID string
+	Scopes  []string
+}
+
+// APIKeyStore looks up metadata for an API key. Implementations may
+// back this with a database, a secrets manager, or (for small
+// deployments) an in-memory map.
+type APIKeyStore interface {
+	Lookup(ctx context.Context, key string) (*APIKey, error)
+}
+
+// APIKeyAuthenticator authenticates requests carrying an API key in the
+// header named HeaderName (default "X-API-Key").
+type APIKeyAuthenticator struct {
+	Store      APIKeyStore
+	HeaderName string
+}
+
+// NewAPIKeyAuthenticator returns an APIKeyAuthenticator backed by store.
+// If headerName is empty, "X-API-Key" is used.
+func NewAPIKeyAuthenticator(store APIKeyStore, headerName string) *APIKeyAuthenticator {
+	if headerName == "" {
+		headerName = "X-API-Key"
+	}
+	return &APIKeyAuthenticator{Store: store, HeaderName: headerName}
+}
+
+// Scheme implements Authenticator. API keys have no standard
+// WWW-Authenticate scheme; callers typically pair this authenticator
+// with Bearer or Basic for the challenge response.
+func (a *APIKeyAuthenticator) Scheme() string { return "Bearer" }
+
+// Authenticate implements Authenticator.
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	key := r.Header.Get(a.HeaderName)
+	if key == "" {
+		return nil, ErrUnauthenticated
+	}
+	rec, err := a.Store.Lookup(r.Context(), key)
+	if err != nil || rec == nil {
+		return nil, ErrInvalidCredential
+	}
+	return &Principal{ID: rec.OwnerID, Scopes: rec.Scopes, Method: "apikey"}, nil
+}
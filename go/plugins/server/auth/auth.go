@@ -0,0 +1,102 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package auth provides pluggable authentication and authorization
+// middleware for Genkit flow HTTP servers. It replaces ad-hoc header
+// inspection inside flow bodies with a single middleware chain that
+// resolves an [Principal] from the incoming request and attaches it to
+// the flow's context.
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// principalContextKey is the context key under which the resolved
+// [Principal] for the current request is stored.
+type principalContextKey struct{}
+
+// Principal describes the authenticated caller of a flow request. It is
+// populated by an [Authenticator] and is the typed replacement for
+// reading raw [http.Header] values out of context.
+type Principal struct {
+	// ID is the authenticator-specific identifier for the caller, e.g.
+	// the JWT "sub" claim, the API key's owner, or the basic-auth
+	// username.
+	ID string
+
+	// Scopes are the permissions granted to this principal. Policies
+	// registered with WithFlowPolicy are evaluated against this set.
+	Scopes []string
+
+	// Claims holds any additional claim-to-context data mapped from the
+	// credential, such as JWT claims. Of the built-in authenticators only
+	// JWTAuthenticator sets it; the others leave it nil.
+	Claims map[string]any
+
+	// Method identifies which Authenticator produced this Principal,
+	// e.g. "bearer", "jwt", "basic", or "apikey".
+	Method string
+}
+
+// HasScope reports whether p has been granted scope.
+func (p *Principal) HasScope(scope string) bool {
+	if p == nil {
+		return false
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// WithPrincipal returns a copy of ctx carrying p.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, p)
+}
+
+// FromContext extracts the Principal previously attached by the auth
+// middleware, if any.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalContextKey{}).(*Principal)
+	return p, ok
+}
+
+// ErrUnauthenticated is returned by an [Authenticator] when the request
+// carries no usable credential at all.
+var ErrUnauthenticated = errors.New("auth: request is missing credentials")
+
+// ErrInvalidCredential is returned by an [Authenticator] when a
+// credential was present but failed verification.
+var ErrInvalidCredential = errors.New("auth: credential is invalid")
+
+// Authenticator resolves a Principal from an inbound HTTP request.
+// Implementations should return ErrUnauthenticated or
+// ErrInvalidCredential (or an error wrapping one of them) so the
+// middleware can emit the correct challenge and status code.
+type Authenticator interface {
+	// Authenticate inspects r and returns the resolved Principal, or an
+	// error if the request cannot be authenticated.
+	Authenticate(r *http.Request) (*Principal, error)
+
+	// Scheme is the WWW-Authenticate scheme this authenticator
+	// challenges with, e.g. "Bearer" or "Basic".
+	Scheme() string
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"net/http"
+	"strings"
+)
+
+// StaticBearerAuthenticator authenticates requests against a fixed set of
+// bearer tokens, mapping each to the Scopes it grants. It is intended for
+// small deployments and tests; production deployments will usually
+// prefer JWTAuthenticator or APIKeyAuthenticator.
+type StaticBearerAuthenticator struct {
+	// Tokens maps a bearer token to the scopes it is granted.
+	Tokens map[string][]string
+}
+
+// NewStaticBearerAuthenticator returns an Authenticator that accepts any
+// token present in tokens, granting the associated scopes.
+func NewStaticBearerAuthenticator(tokens map[string][]string) *StaticBearerAuthenticator {
+	return &StaticBearerAuthenticator{Tokens: tokens}
+}
+
+// Scheme implements Authenticator.
+func (a *StaticBearerAuthenticator) Scheme() string { return "Bearer" }
+
+// Authenticate implements Authenticator.
+func (a *StaticBearerAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	token, ok := bearerToken(r)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	scopes, ok := a.Tokens[token]
+	if !ok {
+		return nil, ErrInvalidCredential
+	}
+	return &Principal{ID: token, Scopes: scopes, Method: "bearer"}, nil
+}
+
+// bearerToken extracts the token from a "Bearer <token>" Authorization
+// header, if present.
+func bearerToken(r *http.Request) (string, bool) {
+	h := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(h, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(h, prefix), true
+}
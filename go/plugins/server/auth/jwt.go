@@ -0,0 +1,191 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// JWKSFetcher retrieves the current JSON Web Key Set from a remote
+// issuer. It is satisfied by jwt libraries' own keyfunc/keyset types;
+// JWTAuthenticator only depends on this narrow interface so callers can
+// plug in whichever JWT library they already use.
+type JWKSFetcher interface {
+	// Fetch returns the current set of keys, keyed by "kid".
+	Fetch(ctx context.Context) (map[string]any, error)
+}
+
+// TokenVerifier validates a raw JWT against the current key set and
+// returns its claims. Implementations are expected to check standard
+// claims (exp, nbf, iss, aud) themselves.
+type TokenVerifier interface {
+	Verify(ctx context.Context, rawToken string, keys map[string]any) (claims map[string]any, err error)
+}
+
+// ClaimMapping describes how JWT claims are projected onto a Principal.
+type ClaimMapping struct {
+	// SubjectClaim is the claim used to populate Principal.ID. Defaults
+	// to "sub".
+	SubjectClaim string
+
+	// ScopeClaim is the claim holding a space-delimited (OAuth2 "scope")
+	// or list-valued set of scopes. Defaults to "scope".
+	ScopeClaim string
+}
+
+// JWTAuthenticator validates bearer tokens as JWTs signed by keys served
+// from a JWKS endpoint, refreshing the key set on a timer, and maps
+// claims onto a Principal.
+type JWTAuthenticator struct {
+	Fetcher  JWKSFetcher
+	Verifier TokenVerifier
+	Mapping  ClaimMapping
+
+	refreshInterval time.Duration
+
+	mu   sync.RWMutex
+	keys map[string]any
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewJWTAuthenticator returns a JWTAuthenticator that keeps its key set
+// fresh by polling fetcher every refreshInterval (a zero or negative
+// interval disables background refresh; keys are then fetched lazily on
+// first use and cached for the process lifetime).
+func NewJWTAuthenticator(fetcher JWKSFetcher, verifier TokenVerifier, mapping ClaimMapping, refreshInterval time.Duration) *JWTAuthenticator {
+	if mapping.SubjectClaim == "" {
+		mapping.SubjectClaim = "sub"
+	}
+	if mapping.ScopeClaim == "" {
+		mapping.ScopeClaim = "scope"
+	}
+	a := &JWTAuthenticator{
+		Fetcher:         fetcher,
+		Verifier:        verifier,
+		Mapping:         mapping,
+		refreshInterval: refreshInterval,
+		stop:            make(chan struct{}),
+	}
+	if refreshInterval > 0 {
+		go a.refreshLoop()
+	}
+	return a
+}
+
+// Close stops the background JWKS refresh goroutine, if running.
+func (a *JWTAuthenticator) Close() {
+	a.once.Do(func() { close(a.stop) })
+}
+
+func (a *JWTAuthenticator) refreshLoop() {
+	ticker := time.NewTicker(a.refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.stop:
+			return
+		case <-ticker.C:
+			_ = a.refresh(context.Background())
+		}
+	}
+}
+
+func (a *JWTAuthenticator) refresh(ctx context.Context) error {
+	keys, err := a.Fetcher.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.keys = keys
+	a.mu.Unlock()
+	return nil
+}
+
+func (a *JWTAuthenticator) currentKeys(ctx context.Context) (map[string]any, error) {
+	a.mu.RLock()
+	keys := a.keys
+	a.mu.RUnlock()
+	if keys != nil {
+		return keys, nil
+	}
+	if err := a.refresh(ctx); err != nil {
+		return nil, err
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.keys, nil
+}
+
+// Scheme implements Authenticator.
+func (a *JWTAuthenticator) Scheme() string { return "Bearer" }
+
+// Authenticate implements Authenticator.
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	token, ok := bearerToken(r)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	ctx := r.Context()
+	keys, err := a.currentKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: fetching JWKS: %v", ErrInvalidCredential, err)
+	}
+
+	claims, err := a.Verifier.Verify(ctx, token, keys)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredential, err)
+	}
+
+	p := &Principal{Claims: claims, Method: "jwt"}
+	if sub, ok := claims[a.Mapping.SubjectClaim].(string); ok {
+		p.ID = sub
+	}
+	switch v := claims[a.Mapping.ScopeClaim].(type) {
+	case string:
+		p.Scopes = splitScopes(v)
+	case []string:
+		p.Scopes = v
+	case []any:
+		for _, s := range v {
+			if str, ok := s.(string); ok {
+				p.Scopes = append(p.Scopes, str)
+			}
+		}
+	}
+	return p, nil
+}
+
+func splitScopes(s string) []string {
+	var scopes []string
+	start := 0
+	for i := 0; i <= len(s); i++ {
+		if i == len(s) || s[i] == ' ' {
+			if i > start {
+				scopes = append(scopes, s[start:i])
+			}
+			start = i + 1
+		}
+	}
+	return scopes
+}
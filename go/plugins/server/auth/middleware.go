@@ -0,0 +1,146 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Middleware authenticates every request with authenticators (tried in
+// order until one succeeds) before delegating to next, attaching the
+// resolved Principal to the request context. It is meant to wrap the
+// mux passed to server.Start, or an individual genkit.Handler, so flow
+// bodies can read auth.FromContext instead of parsing headers
+// themselves.
+//
+// If every authenticator fails, Middleware responds 401 with a
+// WWW-Authenticate challenge listing the attempted schemes. If the
+// request's flow has a registered Policy and the principal fails it,
+// Middleware responds 403.
+func Middleware(next http.Handler, authenticators []Authenticator, opts ...Option) http.Handler {
+	cfg := &config{policies: map[string]Policy{}}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var principal *Principal
+		var lastErr error
+		for _, a := range authenticators {
+			p, err := a.Authenticate(r)
+			if err == nil {
+				principal = p
+				break
+			}
+			lastErr = err
+		}
+
+		if principal == nil {
+			audit(r, nil, lastErr)
+			challenge(w, authenticators)
+			return
+		}
+
+		flowName := flowNameFromPath(r.URL.Path)
+		if policy, ok := cfg.policies[flowName]; ok {
+			if err := policy(principal); err != nil {
+				audit(r, principal, err)
+				http.Error(w, "forbidden: "+err.Error(), http.StatusForbidden)
+				return
+			}
+		}
+
+		audit(r, principal, nil)
+		next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+	})
+}
+
+// config holds the per-flow policies accumulated from WithFlowPolicy
+// options.
+type config struct {
+	policies map[string]Policy
+}
+
+// Option configures Middleware.
+type Option func(*config)
+
+// WithFlowPolicy registers an authorization Policy for the named flow.
+// Requests to flows without a registered policy are allowed through to
+// the flow once authenticated; registering one or more policies does
+// not implicitly restrict flows that have none.
+func WithFlowPolicy(flowName string, policy Policy) Option {
+	return func(c *config) {
+		c.policies[flowName] = policy
+	}
+}
+
+// flowNameFromPath derives the flow name genkit mounts a handler under
+// (the final path segment) from the request path.
+func flowNameFromPath(path string) string {
+	path = strings.TrimSuffix(path, "/")
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		return path[i+1:]
+	}
+	return path
+}
+
+// challenge responds 401 with a WWW-Authenticate header listing every
+// scheme the caller could have used.
+func challenge(w http.ResponseWriter, authenticators []Authenticator) {
+	seen := map[string]bool{}
+	for _, a := range authenticators {
+		scheme := a.Scheme()
+		if seen[scheme] {
+			continue
+		}
+		seen[scheme] = true
+		w.Header().Add("WWW-Authenticate", scheme)
+	}
+	http.Error(w, "unauthorized", http.StatusUnauthorized)
+}
+
+// audit emits a structured zerolog event recording the outcome of an
+// authentication attempt.
+func audit(r *http.Request, p *Principal, err error) {
+	ev := log.Info()
+	if err != nil {
+		ev = log.Warn()
+	}
+	ev = ev.Str("path", r.URL.Path).Str("remote_addr", r.RemoteAddr)
+	if p != nil {
+		ev = ev.Str("principal", p.ID).Str("auth_method", p.Method)
+	}
+	if err != nil {
+		var unauth, invalid error
+		if errors.Is(err, ErrUnauthenticated) {
+			unauth = err
+		}
+		if errors.Is(err, ErrInvalidCredential) {
+			invalid = err
+		}
+		if unauth == nil && invalid == nil {
+			ev = ev.Err(err)
+		} else {
+			ev = ev.AnErr("reason", err)
+		}
+	}
+	ev.Msg("auth: request audited")
+}
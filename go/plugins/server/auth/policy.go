@@ -0,0 +1,46 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import "fmt"
+
+// Policy authorizes an already-authenticated Principal for a specific
+// flow invocation. A nil error means the principal is authorized.
+type Policy func(p *Principal) error
+
+// RequireScope returns a Policy that rejects principals missing scope.
+func RequireScope(scope string) Policy {
+	return func(p *Principal) error {
+		if !p.HasScope(scope) {
+			return fmt.Errorf("principal %q lacks required scope %q", p.ID, scope)
+		}
+		return nil
+	}
+}
+
+// RequireAnyScope returns a Policy that accepts a principal holding at
+// least one of scopes.
+func RequireAnyScope(scopes ...string) Policy {
+	return func(p *Principal) error {
+		for _, s := range scopes {
+			if p.HasScope(s) {
+				return nil
+			}
+		}
+		return fmt.Errorf("principal %q lacks any of required scopes %v", p.ID, scopes)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package contentneg adds content negotiation to Genkit flow HTTP
+// servers so the same flow can be served as JSON, NDJSON, YAML, CBOR,
+// Protobuf, or Server-Sent Events depending on the caller's Accept
+// header, instead of every handler hardcoding application/json.
+package contentneg
+
+import (
+	"io"
+	"sync"
+)
+
+// Codec encodes and decodes values for a single MIME type.
+type Codec interface {
+	// ContentType is the MIME type this codec produces and consumes,
+	// e.g. "application/json".
+	ContentType() string
+
+	// Encode writes v to w in this codec's wire format.
+	Encode(w io.Writer, v any) error
+
+	// Decode reads a value of this codec's wire format from r into v.
+	Decode(r io.Reader, v any) error
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Codec{
+		JSONCodec{}.ContentType():     JSONCodec{},
+		NDJSONCodec{}.ContentType():   NDJSONCodec{},
+		YAMLCodec{}.ContentType():     YAMLCodec{},
+		CBORCodec{}.ContentType():     CBORCodec{},
+		ProtobufCodec{}.ContentType(): ProtobufCodec{},
+	}
+
+	// registryOrder is the server's preference order for picking among
+	// several codecs that equally satisfy a wildcard Accept range (e.g.
+	// "*/*"), since ranging over registry directly would pick one at
+	// random. JSON is listed first so it wins ties, matching the
+	// sample's historical default.
+	registryOrder = []string{
+		JSONCodec{}.ContentType(),
+		NDJSONCodec{}.ContentType(),
+		YAMLCodec{}.ContentType(),
+		CBORCodec{}.ContentType(),
+		ProtobufCodec{}.ContentType(),
+	}
+)
+
+// RegisterCodec makes codec available for negotiation under mimeType,
+// overriding any existing codec registered for that type. A new
+// mimeType is appended to the end of the server-preference order used
+// to break wildcard-range ties, behind the built-in codecs. It is safe
+// to call from an init function or from main before server.Start.
+func RegisterCodec(mimeType string, codec Codec) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registry[mimeType]; !exists {
+		registryOrder = append(registryOrder, mimeType)
+	}
+	registry[mimeType] = codec
+}
+
+// lookupCodec returns the codec registered for mimeType, if any.
+func lookupCodec(mimeType string) (Codec, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	c, ok := registry[mimeType]
+	return c, ok
+}
+
+// supportedTypes returns every currently registered MIME type, used to
+// populate the body of a 406 response.
+func supportedTypes() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	types := make([]string, 0, len(registry))
+	for t := range registry {
+		types = append(types, t)
+	}
+	return types
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package contentneg
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/fxamacker/cbor/v2"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+// JSONCodec is the default codec, matching the sample's historical
+// application/json behavior.
+type JSONCodec struct{}
+
+func (JSONCodec) ContentType() string { return "application/json" }
+
+func (JSONCodec) Encode(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+func (JSONCodec) Decode(r io.Reader, v any) error {
+	return json.NewDecoder(r).Decode(v)
+}
+
+// NDJSONCodec encodes one JSON value per line, the format streaming
+// flows use to emit successive chunks without framing.
+type NDJSONCodec struct{}
+
+func (NDJSONCodec) ContentType() string { return "application/x-ndjson" }
+
+func (NDJSONCodec) Encode(w io.Writer, v any) error {
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		return err
+	}
+	if f, ok := w.(interface{ Flush() }); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+func (NDJSONCodec) Decode(r io.Reader, v any) error {
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+		return io.EOF
+	}
+	return json.Unmarshal(scanner.Bytes(), v)
+}
+
+// YAMLCodec encodes and decodes application/yaml.
+type YAMLCodec struct{}
+
+func (YAMLCodec) ContentType() string { return "application/yaml" }
+
+func (YAMLCodec) Encode(w io.Writer, v any) error {
+	return yaml.NewEncoder(w).Encode(v)
+}
+
+func (YAMLCodec) Decode(r io.Reader, v any) error {
+	return yaml.NewDecoder(r).Decode(v)
+}
+
+// CBORCodec encodes and decodes application/cbor, a compact binary
+// format useful for low-latency or bandwidth-constrained clients.
+type CBORCodec struct{}
+
+func (CBORCodec) ContentType() string { return "application/cbor" }
+
+func (CBORCodec) Encode(w io.Writer, v any) error {
+	enc := cbor.NewEncoder(w)
+	return enc.Encode(v)
+}
+
+func (CBORCodec) Decode(r io.Reader, v any) error {
+	dec := cbor.NewDecoder(r)
+	return dec.Decode(v)
+}
+
+// ProtobufCodec encodes and decodes application/x-protobuf. It only
+// supports values implementing proto.Message; flow input/output types
+// that are plain Go structs should negotiate JSON, YAML, or CBOR
+// instead.
+type ProtobufCodec struct{}
+
+func (ProtobufCodec) ContentType() string { return "application/x-protobuf" }
+
+func (ProtobufCodec) Encode(w io.Writer, v any) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("contentneg: %T does not implement proto.Message", v)
+	}
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+func (ProtobufCodec) Decode(r io.Reader, v any) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("contentneg: %T does not implement proto.Message", v)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(b, msg)
+}
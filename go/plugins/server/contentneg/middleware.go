@@ -0,0 +1,66 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package contentneg
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DecodeRequest decodes r's body into v using the codec registered for
+// r's Content-Type header, defaulting to JSON when the header is absent
+// (matching the sample's historical behavior).
+func DecodeRequest(r *http.Request, v any) error {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = JSONCodec{}.ContentType()
+	}
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = strings.TrimSpace(contentType[:i])
+	}
+	codec, ok := lookupCodec(contentType)
+	if !ok {
+		return fmt.Errorf("contentneg: no codec registered for Content-Type %q", contentType)
+	}
+	return codec.Decode(r.Body, v)
+}
+
+// EncodeResponse negotiates a codec against r's Accept header and
+// writes v to w using it, setting the Content-Type header accordingly.
+// If no registered codec satisfies the Accept header, it writes 406 Not
+// Acceptable with a JSON body listing the supported types and returns
+// only the error, if any, from writing that body.
+func EncodeResponse(w http.ResponseWriter, r *http.Request, v any) error {
+	codec, ok := Negotiate(r.Header.Get("Accept"))
+	if !ok {
+		return writeNotAcceptable(w)
+	}
+	w.Header().Set("Content-Type", codec.ContentType())
+	return codec.Encode(w, v)
+}
+
+// writeNotAcceptable writes a 406 response listing every codec
+// currently registered via RegisterCodec.
+func writeNotAcceptable(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", JSONCodec{}.ContentType())
+	w.WriteHeader(http.StatusNotAcceptable)
+	return JSONCodec{}.Encode(w, map[string]any{
+		"error":     "not acceptable",
+		"supported": SupportedTypes(),
+	})
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package contentneg
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// mediaRange is one comma-separated entry of an Accept header, with its
+// RFC 7231 q-value.
+type mediaRange struct {
+	typ string
+	q   float64
+}
+
+// parseAccept parses an Accept header into media ranges ordered from
+// most to least preferred. A missing or empty header is treated as
+// "*/*".
+func parseAccept(header string) []mediaRange {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return []mediaRange{{typ: "*/*", q: 1}}
+	}
+
+	var ranges []mediaRange
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		segments := strings.Split(part, ";")
+		typ := strings.TrimSpace(segments[0])
+		q := 1.0
+		for _, param := range segments[1:] {
+			param = strings.TrimSpace(param)
+			if v, ok := strings.CutPrefix(param, "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		ranges = append(ranges, mediaRange{typ: typ, q: q})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+	return ranges
+}
+
+// matches reports whether the concrete mimeType satisfies range r,
+// honoring "*/*" and "type/*" wildcards.
+func (r mediaRange) matches(mimeType string) bool {
+	if r.typ == "*/*" {
+		return true
+	}
+	if strings.HasSuffix(r.typ, "/*") {
+		prefix := strings.TrimSuffix(r.typ, "*")
+		return strings.HasPrefix(mimeType, prefix)
+	}
+	return r.typ == mimeType
+}
+
+// Negotiate selects the best registered Codec for the given Accept
+// header, in client-preference order. It returns ok=false if no
+// registered codec satisfies any range with q > 0, in which case
+// callers should respond 406 Not Acceptable with SupportedTypes().
+func Negotiate(acceptHeader string) (codec Codec, ok bool) {
+	for _, r := range parseAccept(acceptHeader) {
+		if r.q <= 0 {
+			continue
+		}
+		registryMu.RLock()
+		for _, mimeType := range registryOrder {
+			if r.matches(mimeType) {
+				c := registry[mimeType]
+				registryMu.RUnlock()
+				return c, true
+			}
+		}
+		registryMu.RUnlock()
+	}
+	return nil, false
+}
+
+// SupportedTypes returns every MIME type currently registered for
+// negotiation, for inclusion in a 406 response body.
+func SupportedTypes() []string {
+	return supportedTypes()
+}
@@ -0,0 +1,65 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package contentneg
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		want   string
+		wantOK bool
+	}{
+		{"empty header defaults to JSON", "", "application/json", true},
+		{"bare wildcard picks JSON deterministically", "*/*", "application/json", true},
+		{"exact match", "application/yaml", "application/yaml", true},
+		{"q-value preference order", "application/cbor;q=0.5, application/yaml;q=0.9", "application/yaml", true},
+		{"wildcard subtype falls back to preference order", "application/*", "application/json", true},
+		{"unregistered type with no fallback", "application/does-not-exist", "", false},
+		{"zero q disqualifies a range", "application/json;q=0, application/yaml", "application/yaml", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codec, ok := Negotiate(tt.accept)
+			if ok != tt.wantOK {
+				t.Fatalf("Negotiate(%q) ok = %v, want %v", tt.accept, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got := codec.ContentType(); got != tt.want {
+				t.Errorf("Negotiate(%q) = %q, want %q", tt.accept, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNegotiate_WildcardIsDeterministic guards against the registry's
+// map iteration leaking back in: a bare "*/*" must resolve to the same
+// codec every time, not a random registered one.
+func TestNegotiate_WildcardIsDeterministic(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		codec, ok := Negotiate("*/*")
+		if !ok {
+			t.Fatalf("Negotiate(\"*/*\") ok = false on iteration %d", i)
+		}
+		if got := codec.ContentType(); got != "application/json" {
+			t.Fatalf("Negotiate(\"*/*\") = %q on iteration %d, want application/json", got, i)
+		}
+	}
+}
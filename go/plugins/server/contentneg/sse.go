@@ -0,0 +1,103 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package contentneg
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// SSEContentType is the text/event-stream MIME type. It is intentionally
+// not registered with RegisterCodec: SSE streams a sequence of chunks
+// rather than encoding a single value, so it is negotiated and driven
+// separately via IsSSERequested and SSEWriter.
+const SSEContentType = "text/event-stream"
+
+// IsSSERequested reports whether r's Accept header prefers
+// text/event-stream over the registered request/response codecs, so a
+// streaming flow handler can switch from a single negotiated response
+// to an SSE stream. A bare "*/*" range (including a missing Accept
+// header, which parseAccept treats the same way) does not count as
+// requesting SSE — text/event-stream must be named at least as
+// specifically as "text/*" before a registered codec is, or it isn't
+// considered requested.
+func IsSSERequested(r *http.Request) bool {
+	for _, rng := range parseAccept(r.Header.Get("Accept")) {
+		if rng.q <= 0 {
+			continue
+		}
+		if rng.typ == "*/*" {
+			return false
+		}
+		if rng.typ == SSEContentType || rng.typ == "text/*" {
+			return true
+		}
+		if _, ok := lookupCodec(rng.typ); ok {
+			return false
+		}
+	}
+	return false
+}
+
+// SSEWriter streams successive flow chunks to w as Server-Sent Events.
+// Callers create one per request, call Send for each streamed chunk,
+// and discard it once the flow completes.
+type SSEWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	codec   Codec
+}
+
+// NewSSEWriter prepares w to stream Server-Sent Events, encoding each
+// event's data payload with codec (typically JSONCodec{}).
+func NewSSEWriter(w http.ResponseWriter, codec Codec) (*SSEWriter, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, fmt.Errorf("contentneg: ResponseWriter does not support flushing, cannot stream SSE")
+	}
+	w.Header().Set("Content-Type", SSEContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	return &SSEWriter{w: w, flusher: flusher, codec: codec}, nil
+}
+
+// Send writes one SSE "message" event carrying chunk, then flushes.
+func (s *SSEWriter) Send(chunk any) error {
+	if _, err := fmt.Fprint(s.w, "event: message\ndata: "); err != nil {
+		return err
+	}
+	if err := s.codec.Encode(s.w, chunk); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprint(s.w, "\n\n"); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+// Close writes the terminal "done" event signaling the stream is
+// complete.
+func (s *SSEWriter) Close() error {
+	if _, err := fmt.Fprint(s.w, "event: done\ndata: {}\n\n"); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
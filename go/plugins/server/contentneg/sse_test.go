@@ -0,0 +1,53 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package contentneg
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestIsSSERequested(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		want   bool
+	}{
+		{"no Accept header", "", false},
+		{"bare wildcard, e.g. curl's default", "*/*", false},
+		{"exact SSE type", SSEContentType, true},
+		{"text wildcard", "text/*", true},
+		{"registered codec wins over a later SSE range", "application/json, text/event-stream;q=0.5", false},
+		{"SSE preferred via q-value", "application/json;q=0.5, text/event-stream", true},
+		{"unregistered, unrelated type falls through to false", "application/does-not-exist", false},
+		{"zero-q SSE range does not count", "text/event-stream;q=0", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "/simpleGreeting", nil)
+			if err != nil {
+				t.Fatalf("http.NewRequest: %v", err)
+			}
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			if got := IsSSERequested(req); got != tt.want {
+				t.Errorf("IsSSERequested() with Accept=%q = %v, want %v", tt.accept, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package otel
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// InstrumentFlow wraps fn, the function normally passed directly to
+// genkit.DefineFlow, with an OpenTelemetry span and the
+// FlowExecutions/InFlightExecutions/PromptLatency metrics. model
+// identifies the default model the flow calls, for metric labels; pass
+// "" if the flow doesn't call a single well-known model.
+//
+//	flow := genkit.DefineFlow(g, "myFlow", otel.InstrumentFlow("myFlow", "gemini-2.0-flash", myFlowFunc))
+func InstrumentFlow[In, Out any](flowName, model string, fn func(context.Context, In) (Out, error)) func(context.Context, In) (Out, error) {
+	return func(ctx context.Context, in In) (out Out, err error) {
+		ctx, span := tracer().Start(ctx, "flow/"+flowName)
+		defer span.End()
+		span.SetAttributes(
+			attribute.String("genkit.flow.name", flowName),
+			attribute.String("genkit.model", model),
+		)
+
+		InFlightExecutions.WithLabelValues(flowName).Inc()
+		defer InFlightExecutions.WithLabelValues(flowName).Dec()
+
+		start := time.Now()
+		out, err = fn(ctx, in)
+		PromptLatency.WithLabelValues(flowName, model).Observe(time.Since(start).Seconds())
+
+		outcome := "success"
+		if err != nil {
+			outcome = "failure"
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		FlowExecutions.WithLabelValues(flowName, model, outcome).Inc()
+		return out, err
+	}
+}
+
+// InstrumentStreamingFlow is the [InstrumentFlow] equivalent for
+// genkit.DefineStreamingFlow handlers. Each invocation of the streaming
+// callback increments StreamedChunks for flowName.
+func InstrumentStreamingFlow[In, Out, Stream any](flowName, model string, fn func(context.Context, In, func(context.Context, Stream) error) (Out, error)) func(context.Context, In, func(context.Context, Stream) error) (Out, error) {
+	return func(ctx context.Context, in In, cb func(context.Context, Stream) error) (out Out, err error) {
+		ctx, span := tracer().Start(ctx, "flow/"+flowName)
+		defer span.End()
+		span.SetAttributes(
+			attribute.String("genkit.flow.name", flowName),
+			attribute.String("genkit.model", model),
+		)
+
+		InFlightExecutions.WithLabelValues(flowName).Inc()
+		defer InFlightExecutions.WithLabelValues(flowName).Dec()
+
+		wrappedCB := cb
+		if cb != nil {
+			wrappedCB = func(ctx context.Context, chunk Stream) error {
+				StreamedChunks.WithLabelValues(flowName).Inc()
+				return cb(ctx, chunk)
+			}
+		}
+
+		start := time.Now()
+		out, err = fn(ctx, in, wrappedCB)
+		PromptLatency.WithLabelValues(flowName, model).Observe(time.Since(start).Seconds())
+
+		outcome := "success"
+		if err != nil {
+			outcome = "failure"
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		FlowExecutions.WithLabelValues(flowName, model, outcome).Inc()
+		return out, err
+	}
+}
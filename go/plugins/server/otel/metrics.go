@@ -0,0 +1,61 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package otel instruments Genkit flows and prompt execution with
+// OpenTelemetry traces and Prometheus metrics, and exposes a /metrics
+// endpoint on the same mux passed to server.Start, so operators get
+// observability without hand-instrumenting every flow.
+package otel
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// PromptLatency records prompt execution latency in seconds,
+	// labeled by flow and model.
+	PromptLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "genkit",
+		Name:      "prompt_latency_seconds",
+		Help:      "Latency of ai.Prompt.Execute calls.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"flow", "model"})
+
+	// FlowExecutions counts flow completions, labeled by flow, model,
+	// and outcome ("success" or "failure").
+	FlowExecutions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "genkit",
+		Name:      "flow_executions_total",
+		Help:      "Count of flow executions by outcome.",
+	}, []string{"flow", "model", "outcome"})
+
+	// StreamedChunks counts chunks emitted by streaming flows, labeled
+	// by flow.
+	StreamedChunks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "genkit",
+		Name:      "flow_streamed_chunks_total",
+		Help:      "Count of chunks emitted by streaming flow executions.",
+	}, []string{"flow"})
+
+	// InFlightExecutions is the number of flow executions currently in
+	// progress, labeled by flow.
+	InFlightExecutions = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "genkit",
+		Name:      "flow_in_flight_executions",
+		Help:      "Number of flow executions currently in progress.",
+	}, []string{"flow"})
+)
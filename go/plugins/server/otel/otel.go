@@ -0,0 +1,69 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package otel
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies this package's instrumentation scope to the
+// configured TracerProvider.
+const tracerName = "github.com/firebase/genkit/go/plugins/server/otel"
+
+var propagator = propagation.TraceContext{}
+
+// WithOTel wires OpenTelemetry tracing and a Prometheus /metrics
+// endpoint into mux, and returns a handler that extracts incoming W3C
+// traceparent headers onto the request context before delegating.
+// Flows defined with InstrumentFlow / InstrumentStreamingFlow pick up
+// the resulting span as their parent, so traces stay connected across
+// the HTTP boundary into model calls made by the googlegenai plugin.
+//
+// There's no MeterProvider argument: the metrics this package records
+// (see metrics.go) are promauto globals on Prometheus's default
+// registry, served directly by promhttp.Handler below, not OTel metric
+// instruments, so a MeterProvider would have nowhere to plug in.
+//
+//	mux := http.NewServeMux()
+//	// ... register flow routes on mux ...
+//	handler := otel.WithOTel(tp)(mux)
+//	server.Start(ctx, addr, handler)
+func WithOTel(tp trace.TracerProvider) func(http.Handler) http.Handler {
+	otel.SetTracerProvider(tp)
+
+	return func(next http.Handler) http.Handler {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+		return mux
+	}
+}
+
+// tracer returns this package's Tracer from whichever TracerProvider is
+// currently registered, so InstrumentFlow doesn't need one threaded
+// through every call site.
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
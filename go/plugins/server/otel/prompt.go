@@ -0,0 +1,61 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package otel
+
+import (
+	"context"
+	"time"
+
+	"github.com/firebase/genkit/go/ai"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// InstrumentPrompt wraps a single prompt.Execute call with a child span
+// recording latency and, once the call returns, input/output token
+// counts from the model's usage metadata. flowName identifies the
+// calling flow for the span name; model is the model being invoked.
+//
+//	resp, err := otel.InstrumentPrompt(ctx, "simpleGreeting", "gemini-2.0-flash", func(ctx context.Context) (*ai.ModelResponse, error) {
+//	    return simpleGreetingPrompt.Execute(ctx, ai.WithInput(input))
+//	})
+func InstrumentPrompt(ctx context.Context, flowName, model string, execute func(context.Context) (*ai.ModelResponse, error)) (*ai.ModelResponse, error) {
+	ctx, span := tracer().Start(ctx, "prompt.Execute/"+flowName)
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("genkit.flow.name", flowName),
+		attribute.String("genkit.model", model),
+	)
+
+	start := time.Now()
+	resp, err := execute(ctx)
+	PromptLatency.WithLabelValues(flowName, model).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+
+	if resp != nil && resp.Usage != nil {
+		span.SetAttributes(
+			attribute.Int("genkit.usage.input_tokens", resp.Usage.InputTokens),
+			attribute.Int("genkit.usage.output_tokens", resp.Usage.OutputTokens),
+		)
+	}
+	return resp, nil
+}
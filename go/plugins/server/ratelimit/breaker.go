@@ -0,0 +1,140 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// breakerState is one of the three states a CircuitBreaker can be in.
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// CircuitBreaker trips when the error ratio of calls routed through it
+// (typically downstream model calls) exceeds Threshold within Window,
+// rejecting further calls for Cooldown before allowing a single
+// half-open probe through.
+type CircuitBreaker struct {
+	// Name identifies the breaker in the errors returned while it is
+	// open, e.g. the model or flow it guards.
+	Name string
+
+	// Threshold is the error ratio (0-1) that trips the breaker.
+	Threshold float64
+
+	// MinSamples is the minimum number of calls observed in Window
+	// before the error ratio is evaluated, avoiding trips on a handful
+	// of early failures.
+	MinSamples int
+
+	// Window is the rolling period over which the error ratio is
+	// computed.
+	Window time.Duration
+
+	// Cooldown is how long the breaker stays open before allowing a
+	// half-open probe.
+	Cooldown time.Duration
+
+	mu        sync.Mutex
+	state     breakerState
+	openedAt  time.Time
+	successes int
+	failures  int
+	windowEnd time.Time
+}
+
+// NewCircuitBreaker returns a closed CircuitBreaker named name with the
+// given thresholds.
+func NewCircuitBreaker(name string, threshold float64, minSamples int, window, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		Name:       name,
+		Threshold:  threshold,
+		MinSamples: minSamples,
+		Window:     window,
+		Cooldown:   cooldown,
+		state:      breakerClosed,
+	}
+}
+
+// Allow reports whether a call should proceed, transitioning an open
+// breaker to half-open once Cooldown has elapsed.
+func (cb *CircuitBreaker) Allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	now := time.Now()
+	if now.After(cb.windowEnd) {
+		cb.successes, cb.failures = 0, 0
+		cb.windowEnd = now.Add(cb.Window)
+	}
+
+	switch cb.state {
+	case breakerOpen:
+		if now.Sub(cb.openedAt) >= cb.Cooldown {
+			cb.state = breakerHalfOpen
+			return true
+		}
+		return false
+	case breakerHalfOpen:
+		// Only the call that transitioned us into breakerHalfOpen above
+		// gets to probe; every other caller is held off until Record
+		// resolves that probe one way or the other.
+		return false
+	default:
+		return true
+	}
+}
+
+// Record reports the outcome of a call that Allow permitted, tripping
+// the breaker if the error ratio over Window now exceeds Threshold, or
+// closing it again after a successful half-open probe.
+func (cb *CircuitBreaker) Record(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == breakerHalfOpen {
+		if err != nil {
+			cb.state = breakerOpen
+			cb.openedAt = time.Now()
+			return
+		}
+		cb.state = breakerClosed
+		cb.successes, cb.failures = 0, 0
+		return
+	}
+
+	if err != nil {
+		cb.failures++
+	} else {
+		cb.successes++
+	}
+
+	total := cb.successes + cb.failures
+	if total < cb.MinSamples {
+		return
+	}
+	if float64(cb.failures)/float64(total) > cb.Threshold {
+		cb.state = breakerOpen
+		cb.openedAt = time.Now()
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker_TripsAfterThreshold(t *testing.T) {
+	cb := NewCircuitBreaker("test", 0.5, 2, time.Minute, time.Minute)
+
+	if !cb.Allow() {
+		t.Fatal("Allow() on a fresh breaker = false, want true")
+	}
+	cb.Record(errors.New("boom"))
+	if !cb.Allow() {
+		t.Fatal("Allow() before MinSamples reached = false, want true")
+	}
+	cb.Record(errors.New("boom"))
+
+	if cb.Allow() {
+		t.Fatal("Allow() after exceeding Threshold = true, want false (breaker should be open)")
+	}
+}
+
+func TestCircuitBreaker_StaysOpenDuringCooldown(t *testing.T) {
+	cb := NewCircuitBreaker("test", 0.5, 1, time.Minute, time.Hour)
+	cb.Allow()
+	cb.Record(errors.New("boom"))
+
+	if cb.Allow() {
+		t.Fatal("Allow() during Cooldown = true, want false")
+	}
+}
+
+func TestCircuitBreaker_HalfOpenAllowsOnlyOneProbe(t *testing.T) {
+	cb := NewCircuitBreaker("test", 0.5, 1, time.Minute, time.Millisecond)
+	cb.Allow()
+	cb.Record(errors.New("boom"))
+
+	time.Sleep(2 * time.Millisecond)
+
+	if !cb.Allow() {
+		t.Fatal("first Allow() after Cooldown = false, want true (the half-open probe)")
+	}
+	for i := 0; i < 5; i++ {
+		if cb.Allow() {
+			t.Fatalf("Allow() call %d while a half-open probe is outstanding = true, want false", i)
+		}
+	}
+}
+
+func TestCircuitBreaker_HalfOpenSuccessCloses(t *testing.T) {
+	cb := NewCircuitBreaker("test", 0.5, 1, time.Minute, time.Millisecond)
+	cb.Allow()
+	cb.Record(errors.New("boom"))
+	time.Sleep(2 * time.Millisecond)
+
+	if !cb.Allow() {
+		t.Fatal("half-open probe Allow() = false, want true")
+	}
+	cb.Record(nil)
+
+	if !cb.Allow() {
+		t.Fatal("Allow() after a successful half-open probe = false, want true (breaker should be closed)")
+	}
+}
+
+func TestCircuitBreaker_HalfOpenFailureReopens(t *testing.T) {
+	cb := NewCircuitBreaker("test", 0.5, 1, time.Minute, time.Millisecond)
+	cb.Allow()
+	cb.Record(errors.New("boom"))
+	time.Sleep(2 * time.Millisecond)
+
+	if !cb.Allow() {
+		t.Fatal("half-open probe Allow() = false, want true")
+	}
+	cb.Record(errors.New("still broken"))
+
+	if cb.Allow() {
+		t.Fatal("Allow() immediately after a failed half-open probe = true, want false (breaker should reopen)")
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Counter tracks token buckets keyed by an arbitrary string, so rate
+// limiting state can live in-process (NewInMemoryCounter) or be shared
+// across replicas (NewRedisCounter).
+type Counter interface {
+	// Allow consumes one token from the bucket for key, creating it
+	// with limit's rate/burst if it doesn't exist yet. It reports
+	// whether the call is allowed and, if not, how long the caller
+	// should wait before retrying.
+	Allow(ctx context.Context, key string, limit Limit) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// defaultCounter backs Wrap/WrapStreaming calls that don't specify
+// WithCounter.
+var defaultCounter = NewInMemoryCounter()
+
+// bucket is a single key's token-bucket state.
+type bucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// InMemoryCounter implements Counter with an in-process map of token
+// buckets. It is the default backend, suitable for single-replica
+// deployments or tests.
+type InMemoryCounter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// NewInMemoryCounter returns an empty in-memory Counter.
+func NewInMemoryCounter() *InMemoryCounter {
+	return &InMemoryCounter{buckets: map[string]*bucket{}}
+}
+
+// Allow implements Counter.
+func (c *InMemoryCounter) Allow(_ context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	b, ok := c.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), lastRefill: now}
+		c.buckets[key] = b
+	}
+
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	b.tokens = min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+	b.lastRefill = now
+
+	if b.tokens < 1 {
+		deficit := 1 - b.tokens
+		retryAfter := time.Duration(deficit/limit.Rate*float64(time.Second)) + time.Millisecond
+		return false, retryAfter, nil
+	}
+
+	b.tokens--
+	return true, 0, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestInMemoryCounter_Allow_BurstThenExhausted(t *testing.T) {
+	c := NewInMemoryCounter()
+	limit := Limit{Rate: 1, Burst: 2}
+	ctx := context.Background()
+
+	for i := 0; i < limit.Burst; i++ {
+		allowed, _, err := c.Allow(ctx, "k", limit)
+		if err != nil {
+			t.Fatalf("Allow() error = %v", err)
+		}
+		if !allowed {
+			t.Fatalf("Allow() call %d = false, want true (within burst)", i)
+		}
+	}
+
+	allowed, retryAfter, err := c.Allow(ctx, "k", limit)
+	if err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+	if allowed {
+		t.Fatalf("Allow() after exhausting burst = true, want false")
+	}
+	if retryAfter <= 0 {
+		t.Errorf("retryAfter = %v, want > 0", retryAfter)
+	}
+}
+
+func TestInMemoryCounter_Allow_RefillsOverTime(t *testing.T) {
+	c := NewInMemoryCounter()
+	limit := Limit{Rate: 1000, Burst: 1}
+	ctx := context.Background()
+
+	if allowed, _, err := c.Allow(ctx, "k", limit); err != nil || !allowed {
+		t.Fatalf("Allow() = %v, %v, want true, nil", allowed, err)
+	}
+	if allowed, _, err := c.Allow(ctx, "k", limit); err != nil || allowed {
+		t.Fatalf("Allow() immediately after exhausting burst = %v, %v, want false, nil", allowed, err)
+	}
+
+	// At Rate=1000/s, a 5ms sleep refills well over the single token
+	// Burst=1 lets the bucket hold.
+	time.Sleep(5 * time.Millisecond)
+	if allowed, _, err := c.Allow(ctx, "k", limit); err != nil || !allowed {
+		t.Fatalf("Allow() after refill = %v, %v, want true, nil", allowed, err)
+	}
+}
+
+func TestInMemoryCounter_Allow_SeparateKeysDontShareBuckets(t *testing.T) {
+	c := NewInMemoryCounter()
+	limit := Limit{Rate: 1, Burst: 1}
+	ctx := context.Background()
+
+	if allowed, _, err := c.Allow(ctx, "a", limit); err != nil || !allowed {
+		t.Fatalf("Allow(a) = %v, %v, want true, nil", allowed, err)
+	}
+	if allowed, _, err := c.Allow(ctx, "b", limit); err != nil || !allowed {
+		t.Fatalf("Allow(b) = %v, %v, want true, nil (separate bucket from a)", allowed, err)
+	}
+}
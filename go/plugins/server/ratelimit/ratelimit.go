@@ -0,0 +1,170 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ratelimit wraps Genkit flow handlers with token-bucket rate
+// limiting, a max-in-flight semaphore, and a circuit breaker around
+// downstream model calls, so expensive and quota-limited LLM calls get
+// a cross-cutting safety net without every flow re-implementing one.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/firebase/genkit/go/plugins/server/auth"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrRateLimited is returned (wrapped) when a call is rejected because
+// its token bucket has no tokens left.
+var ErrRateLimited = errors.New("ratelimit: rate limit exceeded")
+
+// ErrTooManyInFlight is returned (wrapped) when a call is rejected
+// because the flow's in-flight semaphore is full.
+var ErrTooManyInFlight = errors.New("ratelimit: too many concurrent executions")
+
+// ErrCircuitOpen is returned (wrapped) when a call is rejected because
+// the circuit breaker guarding it has tripped.
+var ErrCircuitOpen = errors.New("ratelimit: circuit breaker open")
+
+// RetryAfter, when set on an error returned by a Wrap'd flow, tells
+// callers how long to wait before retrying. Middleware layered in front
+// of the flow (e.g. the HTTP handler) can type-assert for this to set a
+// Retry-After header.
+type RetryAfter interface {
+	RetryAfter() time.Duration
+}
+
+type limitedError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (e *limitedError) Error() string             { return e.err.Error() }
+func (e *limitedError) Unwrap() error             { return e.err }
+func (e *limitedError) RetryAfter() time.Duration { return e.retryAfter }
+
+// KeyFunc derives the rate-limit key (e.g. principal ID, IP, or flow
+// name) for a call from its context.
+type KeyFunc func(ctx context.Context, flowName string) string
+
+// DefaultKeyFunc keys by authenticated principal (see
+// [github.com/firebase/genkit/go/plugins/server/auth]) when present,
+// falling back to the flow name so unauthenticated deployments still
+// get a shared-bucket limit.
+func DefaultKeyFunc(ctx context.Context, flowName string) string {
+	if p, ok := auth.FromContext(ctx); ok && p.ID != "" {
+		return flowName + ":" + p.ID
+	}
+	return flowName
+}
+
+// Limit describes a token-bucket rate: tokens are added at Rate per
+// second up to a maximum of Burst.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// options accumulates the settings applied by Option values.
+type options struct {
+	counter     Counter
+	limit       Limit
+	keyFunc     KeyFunc
+	maxInFlight int
+	breaker     *CircuitBreaker
+}
+
+// Option configures Wrap and WrapStreaming.
+type Option func(*options)
+
+// WithLimit sets the token-bucket rate and burst applied per key.
+func WithLimit(limit Limit) Option {
+	return func(o *options) { o.limit = limit }
+}
+
+// WithCounter overrides the Counter backend (in-memory by default). Use
+// NewRedisCounter to share limits across replicas.
+func WithCounter(c Counter) Option {
+	return func(o *options) { o.counter = c }
+}
+
+// WithKeyFunc overrides how calls are grouped for rate limiting.
+func WithKeyFunc(f KeyFunc) Option {
+	return func(o *options) { o.keyFunc = f }
+}
+
+// WithMaxInFlight caps the number of concurrent executions of the
+// wrapped flow. Zero (the default) disables the semaphore.
+func WithMaxInFlight(n int) Option {
+	return func(o *options) { o.maxInFlight = n }
+}
+
+// WithCircuitBreaker trips calls through cb, short-circuiting them once
+// cb has opened.
+func WithCircuitBreaker(cb *CircuitBreaker) Option {
+	return func(o *options) { o.breaker = cb }
+}
+
+func buildOptions(opts []Option) *options {
+	o := &options{
+		counter: defaultCounter,
+		keyFunc: DefaultKeyFunc,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// guard runs the shared rate-limit / semaphore / circuit-breaker
+// sequence around a single call, invoking next if all checks pass.
+func guard(ctx context.Context, flowName string, o *options, sem *semaphore, next func() error) error {
+	if o.limit.Rate > 0 {
+		key := o.keyFunc(ctx, flowName)
+		allowed, retryAfter, err := o.counter.Allow(ctx, key, o.limit)
+		if err != nil {
+			return fmt.Errorf("ratelimit: counter backend: %w", err)
+		}
+		if !allowed {
+			log.Warn().Str("flow", flowName).Str("key", key).Dur("retry_after", retryAfter).
+				Msg("rate_limited")
+			return &limitedError{err: fmt.Errorf("%w: flow %q", ErrRateLimited, flowName), retryAfter: retryAfter}
+		}
+	}
+
+	if sem != nil {
+		if !sem.TryAcquire() {
+			log.Warn().Str("flow", flowName).Msg("rate_limited")
+			return fmt.Errorf("%w: flow %q", ErrTooManyInFlight, flowName)
+		}
+		defer sem.Release()
+	}
+
+	if o.breaker != nil {
+		if !o.breaker.Allow() {
+			log.Warn().Str("flow", flowName).Msg("circuit_open")
+			return fmt.Errorf("%w: %q", ErrCircuitOpen, o.breaker.Name)
+		}
+		err := next()
+		o.breaker.Record(err)
+		return err
+	}
+
+	return next()
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucketScript implements the same refill-then-consume logic as
+// InMemoryCounter.Allow, but atomically in Redis so multiple replicas
+// share one bucket per key. KEYS[1] is the bucket key; ARGV is
+// rate, burst, now (unix seconds, float). Each call consumes one token.
+var tokenBucketScript = redis.NewScript(`
+local key = KEYS[1]
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call("HMGET", key, "tokens", "ts")
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil then
+  tokens = burst
+  ts = now
+end
+
+local elapsed = math.max(0, now - ts)
+tokens = math.min(burst, tokens + elapsed * rate)
+
+local allowed = 0
+if tokens >= 1 then
+  allowed = 1
+  tokens = tokens - 1
+end
+
+redis.call("HMSET", key, "tokens", tokens, "ts", now)
+redis.call("EXPIRE", key, math.ceil(burst / rate) + 1)
+
+return {allowed, tokens}
+`)
+
+// RedisCounter implements Counter on top of a Redis client, so rate
+// limits are shared across every replica of a multi-instance
+// deployment.
+type RedisCounter struct {
+	client redis.Scripter
+	prefix string
+}
+
+// NewRedisCounter returns a Counter backed by client. Keys are stored
+// under "ratelimit:<key>" unless keyPrefix overrides the prefix.
+func NewRedisCounter(client redis.Scripter, keyPrefix string) *RedisCounter {
+	if keyPrefix == "" {
+		keyPrefix = "ratelimit:"
+	}
+	return &RedisCounter{client: client, prefix: keyPrefix}
+}
+
+// Allow implements Counter.
+func (c *RedisCounter) Allow(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	now := float64(time.Now().UnixNano()) / 1e9
+	res, err := tokenBucketScript.Run(ctx, c.client, []string{c.prefix + key}, limit.Rate, limit.Burst, now).Result()
+	if err != nil {
+		return false, 0, err
+	}
+
+	vals, ok := res.([]any)
+	if !ok || len(vals) != 2 {
+		return false, 0, nil
+	}
+	allowed, _ := vals[0].(int64)
+
+	if allowed == 1 {
+		return true, 0, nil
+	}
+
+	// Redis converts the Lua number returned by the script to an
+	// integer reply, so go-redis always hands this back as int64, never
+	// a string.
+	var tokens float64
+	switch t := vals[1].(type) {
+	case int64:
+		tokens = float64(t)
+	case float64:
+		tokens = t
+	}
+	deficit := 1 - tokens
+	retryAfter := time.Duration(deficit/limit.Rate*float64(time.Second)) + time.Millisecond
+	return false, retryAfter, nil
+}
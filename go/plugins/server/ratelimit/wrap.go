@@ -0,0 +1,61 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import "context"
+
+// Wrap adds rate limiting, a max-in-flight semaphore, and an optional
+// circuit breaker around fn, the function normally passed directly to
+// genkit.DefineFlow. The returned function has the same signature, so
+// it can be substituted in place without changing the flow's
+// registration:
+//
+//	flow := genkit.DefineFlow(g, "myFlow", ratelimit.Wrap("myFlow", myFlowFunc,
+//	    ratelimit.WithLimit(ratelimit.Limit{Rate: 5, Burst: 10}),
+//	    ratelimit.WithMaxInFlight(3),
+//	))
+func Wrap[In, Out any](flowName string, fn func(context.Context, In) (Out, error), opts ...Option) func(context.Context, In) (Out, error) {
+	o := buildOptions(opts)
+	sem := newSemaphore(o.maxInFlight)
+
+	return func(ctx context.Context, in In) (Out, error) {
+		var out Out
+		err := guard(ctx, flowName, o, sem, func() error {
+			var innerErr error
+			out, innerErr = fn(ctx, in)
+			return innerErr
+		})
+		return out, err
+	}
+}
+
+// WrapStreaming is the [Wrap] equivalent for genkit.DefineStreamingFlow
+// handlers, which additionally take a streaming callback.
+func WrapStreaming[In, Out, Stream any](flowName string, fn func(context.Context, In, func(context.Context, Stream) error) (Out, error), opts ...Option) func(context.Context, In, func(context.Context, Stream) error) (Out, error) {
+	o := buildOptions(opts)
+	sem := newSemaphore(o.maxInFlight)
+
+	return func(ctx context.Context, in In, cb func(context.Context, Stream) error) (Out, error) {
+		var out Out
+		err := guard(ctx, flowName, o, sem, func() error {
+			var innerErr error
+			out, innerErr = fn(ctx, in, cb)
+			return innerErr
+		})
+		return out, err
+	}
+}
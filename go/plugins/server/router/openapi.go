@@ -0,0 +1,170 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"reflect"
+	"strings"
+)
+
+// DocInfo populates the "info" section of the generated OpenAPI
+// document.
+type DocInfo struct {
+	Title   string
+	Version string
+}
+
+// document is a minimal OpenAPI 3.1 document, covering just enough of
+// the spec to describe flows mounted via Mount.
+type document struct {
+	OpenAPI string              `json:"openapi"`
+	Info    DocInfo             `json:"info"`
+	Paths   map[string]pathItem `json:"paths"`
+}
+
+type pathItem map[string]operation // keyed by lowercase HTTP method
+
+type operation struct {
+	OperationID string              `json:"operationId"`
+	RequestBody *requestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]response `json:"responses"`
+}
+
+type requestBody struct {
+	Content map[string]mediaType `json:"content"`
+}
+
+type response struct {
+	Description string               `json:"description"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema schema `json:"schema"`
+}
+
+// schema is a (non-exhaustive) JSON Schema, enough to describe the Go
+// struct types genkit flows use for input/output.
+type schema struct {
+	Type       string            `json:"type,omitempty"`
+	Format     string            `json:"format,omitempty"`
+	Items      *schema           `json:"items,omitempty"`
+	Properties map[string]schema `json:"properties,omitempty"`
+	Required   []string          `json:"required,omitempty"`
+}
+
+// openAPIDocument builds the OpenAPI document for every route Mount has
+// registered so far.
+func (r *Router) openAPIDocument() document {
+	paths := map[string]pathItem{}
+	for _, rt := range r.routes {
+		item := paths[rt.path]
+		if item == nil {
+			item = pathItem{}
+		}
+		item[strings.ToLower(rt.method)] = rt.operation()
+		paths[rt.path] = item
+	}
+	return document{OpenAPI: "3.1.0", Info: r.info, Paths: paths}
+}
+
+func (rt *route) operation() operation {
+	responseContent := map[string]mediaType{"application/json": {Schema: reflectSchema(rt.outputType)}}
+	if rt.streaming {
+		responseContent = map[string]mediaType{"text/event-stream": {Schema: schema{Type: "string"}}}
+	}
+
+	op := operation{
+		OperationID: rt.name,
+		Responses: map[string]response{
+			"200": {Description: "Successful response", Content: responseContent},
+		},
+	}
+	if rt.inputType != nil {
+		op.RequestBody = &requestBody{
+			Content: map[string]mediaType{"application/json": {Schema: reflectSchema(rt.inputType)}},
+		}
+	}
+	return op
+}
+
+// reflectSchema derives a JSON Schema from a Go type, following pointers
+// and covering the struct/slice/primitive kinds genkit flow input and
+// output types are built from.
+func reflectSchema(t reflect.Type) schema {
+	if t == nil {
+		return schema{Type: "object"}
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		props := map[string]schema{}
+		var required []string
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, omitempty := jsonFieldName(f)
+			if name == "-" {
+				continue
+			}
+			props[name] = reflectSchema(f.Type)
+			if !omitempty {
+				required = append(required, name)
+			}
+		}
+		return schema{Type: "object", Properties: props, Required: required}
+	case reflect.Slice, reflect.Array:
+		elem := reflectSchema(t.Elem())
+		return schema{Type: "array", Items: &elem}
+	case reflect.String:
+		return schema{Type: "string"}
+	case reflect.Bool:
+		return schema{Type: "boolean"}
+	case reflect.Float32, reflect.Float64:
+		return schema{Type: "number"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return schema{Type: "integer"}
+	default:
+		return schema{Type: "object"}
+	}
+}
+
+// jsonFieldName derives the name an encoding/json-compatible codec
+// would use for f, and whether it's marked omitempty.
+func jsonFieldName(f reflect.StructField) (name string, omitempty bool) {
+	tag := f.Tag.Get("json")
+	if tag == "" {
+		return f.Name, false
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = f.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty
+}
@@ -0,0 +1,153 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package router mounts Genkit flows as HTTP routes and derives an
+// OpenAPI 3.1 document (served at /openapi.json, with a Swagger UI at
+// /docs) from the flow input/output types, instead of every flow
+// needing its own hand-written handler.
+//
+// Call Mount once per flow: Go's generics are resolved statically, so
+// there's no way to range over the flows registered on a *genkit.Genkit
+// instance (each has its own In/Out types erased behind the Runnable
+// interface) and call the generic Mount for each one automatically.
+// Mount still removes the per-flow decode/encode/registration
+// boilerplate; it just can't discover the flow list itself.
+package router
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+
+	"github.com/firebase/genkit/go/plugins/server/contentneg"
+	"github.com/rs/zerolog/log"
+)
+
+// Runnable is satisfied by the *core.Flow value genkit.DefineFlow and
+// genkit.DefineStreamingFlow return; Router only depends on Run so it
+// can mount either kind of flow.
+type Runnable[In, Out any] interface {
+	Run(ctx context.Context, input In) (Out, error)
+}
+
+// route holds everything Router needs to serve and document a single
+// mounted flow.
+type route struct {
+	name       string
+	method     string
+	path       string
+	streaming  bool
+	inputType  reflect.Type
+	outputType reflect.Type
+	handler    http.HandlerFunc
+}
+
+// Router serves the flows registered on it with Mount, by default at
+// POST under its prefix (see WithRoute to override), and exposes the
+// resulting API as an OpenAPI 3.1 document plus a Swagger UI. It does
+// not discover flows on its own; each one needs its own Mount call.
+type Router struct {
+	prefix string
+	info   DocInfo
+	routes []*route
+}
+
+// New returns a Router that mounts flows under prefix (e.g. "/api");
+// pass "" to mount flows at the server root. info populates the
+// OpenAPI document's title/version.
+func New(prefix string, info DocInfo) *Router {
+	return &Router{prefix: prefix, info: info}
+}
+
+// RouteOption configures a single Mount call.
+type RouteOption func(*route)
+
+// WithRoute overrides the default method ("POST") and path
+// (Prefix+"/"+flow name) a flow is mounted under, e.g.:
+//
+//	router.Mount(r, "testAllCoffeeFlows", coffeeFlow, router.WithRoute("POST", "/coffee/test"))
+func WithRoute(method, path string) RouteOption {
+	return func(rt *route) {
+		rt.method = method
+		rt.path = path
+	}
+}
+
+// Streaming marks the route as a streaming flow, so the generated
+// OpenAPI document declares a text/event-stream response instead of a
+// single JSON body.
+func Streaming() RouteOption {
+	return func(rt *route) { rt.streaming = true }
+}
+
+// Mount registers flow under name at POST prefix+"/"+name (or the
+// method and path given by WithRoute), deriving its OpenAPI schema from
+// In/Out's Go types. Call this once per flow before Handler.
+func Mount[In, Out any](r *Router, name string, flow Runnable[In, Out], opts ...RouteOption) {
+	var in In
+	var out Out
+	rt := &route{
+		name:       name,
+		method:     http.MethodPost,
+		path:       r.prefix + "/" + name,
+		inputType:  reflect.TypeOf(in),
+		outputType: reflect.TypeOf(out),
+	}
+	for _, opt := range opts {
+		opt(rt)
+	}
+
+	rt.handler = func(w http.ResponseWriter, req *http.Request) {
+		var input In
+		if req.ContentLength > 0 {
+			if err := contentneg.DecodeRequest(req, &input); err != nil {
+				http.Error(w, "invalid input: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		output, err := flow.Run(req.Context(), input)
+		if err != nil {
+			log.Error().Err(err).Str("flow", name).Msg("router: flow execution failed")
+			http.Error(w, "flow error: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if err := contentneg.EncodeResponse(w, req, output); err != nil {
+			log.Error().Err(err).Str("flow", name).Msg("router: failed to encode response")
+		}
+	}
+
+	r.routes = append(r.routes, rt)
+}
+
+// Handler returns an http.Handler serving every route registered via
+// Mount, plus /openapi.json and /docs.
+func (r *Router) Handler() http.Handler {
+	mux := http.NewServeMux()
+	for _, rt := range r.routes {
+		mux.HandleFunc(rt.method+" "+rt.path, rt.handler)
+	}
+	mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = contentneg.JSONCodec{}.Encode(w, r.openAPIDocument())
+	})
+	mux.HandleFunc("GET /docs", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(swaggerUIPage))
+	})
+	return mux
+}
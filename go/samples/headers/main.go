@@ -21,29 +21,32 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strings"
+	"time"
 
 	"github.com/firebase/genkit/go/ai"
 	"github.com/firebase/genkit/go/genkit"
+	"github.com/firebase/genkit/go/genkit/session"
 	"github.com/firebase/genkit/go/plugins/googlegenai"
 	"github.com/firebase/genkit/go/plugins/server"
+	"github.com/firebase/genkit/go/plugins/server/auth"
+	"github.com/firebase/genkit/go/plugins/server/contentneg"
+	otelserver "github.com/firebase/genkit/go/plugins/server/otel"
+	"github.com/firebase/genkit/go/plugins/server/ratelimit"
+	"github.com/firebase/genkit/go/plugins/server/router"
 	"github.com/rs/zerolog/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
-// HeaderContextKey 用于存储 http.Header
-type HeaderContextKey struct{}
+// modelBreaker trips once googlegenai calls making up simpleGreeting and
+// greetingWithHistory start erroring (e.g. 429/5xx) more than 50% of the
+// time over a one-minute window, so a struggling model doesn't get
+// hammered with retries from every caller.
+var modelBreaker = ratelimit.NewCircuitBreaker("gemini-2.0-flash", 0.5, 5, time.Minute, 30*time.Second)
 
-// WithHeaders 将 header 存入 context
-func WithHeaders(ctx context.Context, headers http.Header) context.Context {
-    return context.WithValue(ctx, HeaderContextKey{}, headers)
-}
-
-// GetHeaders 提取 header
-func GetHeaders(ctx context.Context) http.Header {
-    if headers, ok := ctx.Value(HeaderContextKey{}).(http.Header); ok {
-        return headers
-    }
-    return nil
+// staticTokens 是本示例使用的静态 bearer token，生产环境应换成
+// auth.NewJWTAuthenticator 或 auth.NewAPIKeyAuthenticator。
+var staticTokens = map[string][]string{
+	"test-token": {"coffee:test"},
 }
 
 const simpleGreetingPromptTemplate = `
@@ -76,6 +79,14 @@ type customerTimeAndHistoryInput struct {
     PreviousOrder string `json:"previousOrder"`
 }
 
+// greetingWithHistoryInput is what callers of the HTTP endpoint send;
+// CustomerName and PreviousOrder no longer need to be passed by hand on
+// every request, since they're read from the session's per-customer
+// state (see chatStore below) instead.
+type greetingWithHistoryInput struct {
+    CurrentTime string `json:"currentTime"`
+}
+
 type testAllCoffeeFlowsOutput struct {
     Pass    bool     `json:"pass"`
     Replies []string `json:"replies,omitempty"`
@@ -94,6 +105,14 @@ func main() {
 
     m := googlegenai.GoogleAIModel(g, "gemini-2.0-flash")
 
+    // chatStore persists greetingWithHistory's per-customer state and
+    // message history across requests, keyed by the session ID that
+    // session.Middleware resolves below. NewInMemoryStore is fine for
+    // this sample; a real deployment would reach for
+    // session.NewRedisStore or session.NewSQLStore instead so sessions
+    // survive a restart.
+    chatStore := session.NewInMemoryStore()
+
     // 定义 simpleGreeting Prompt 和 Flow
     simpleGreetingPrompt, err := genkit.DefinePrompt(g, "simpleGreeting",
         ai.WithPrompt(simpleGreetingPromptTemplate),
@@ -105,44 +124,45 @@ func main() {
         log.Fatal().Msg(err.Error())
     }
 
-    simpleGreetingFlow := genkit.DefineStreamingFlow(g, "simpleGreeting", func(ctx context.Context, input *simpleGreetingInput, cb func(context.Context, string) error) (string, error) {
-        // 提取 HTTP header
-        headers := GetHeaders(ctx)
-        if headers != nil {
-            log.Info().
-                Str("authorization", headers.Get("Authorization")).
-                Str("x-request-id", headers.Get("X-Request-ID")).
-                Msg("Received HTTP headers in simpleGreeting")
-        }
-
-        // 验证 header
-        if headers != nil && !strings.HasPrefix(headers.Get("Authorization"), "Bearer ") {
-            return "", fmt.Errorf("invalid authorization header")
-        }
-
-        // 日志输入
-        inputJSON, err := json.Marshal(input)
-        if err != nil {
-            return "", fmt.Errorf("json.Marshal: %w", err)
-        }
-        log.Info().Msgf("input--------%s", string(inputJSON))
+    simpleGreetingFlow := genkit.DefineStreamingFlow(g, "simpleGreeting", otelserver.InstrumentStreamingFlow("simpleGreeting", "gemini-2.0-flash", ratelimit.WrapStreaming("simpleGreeting",
+        func(ctx context.Context, input *simpleGreetingInput, cb func(context.Context, string) error) (string, error) {
+            // 解析已认证的调用者（由 auth.Middleware 注入）
+            if p, ok := auth.FromContext(ctx); ok {
+                log.Info().
+                    Str("principal", p.ID).
+                    Str("auth_method", p.Method).
+                    Msg("Received authenticated request in simpleGreeting")
+            }
 
-        // 执行 prompt
-        var callback func(context.Context, *ai.ModelResponseChunk) error
-        if cb != nil {
-            callback = func(ctx context.Context, c *ai.ModelResponseChunk) error {
-                return cb(ctx, c.Text())
+            // 日志输入
+            inputJSON, err := json.Marshal(input)
+            if err != nil {
+                return "", fmt.Errorf("json.Marshal: %w", err)
             }
-        }
-        resp, err := simpleGreetingPrompt.Execute(ctx,
-            ai.WithInput(input),
-            ai.WithStreaming(callback),
-        )
-        if err != nil {
-            return "", err
-        }
-        return resp.Text(), nil
-    })
+            log.Info().Msgf("input--------%s", string(inputJSON))
+
+            // 执行 prompt
+            var callback func(context.Context, *ai.ModelResponseChunk) error
+            if cb != nil {
+                callback = func(ctx context.Context, c *ai.ModelResponseChunk) error {
+                    return cb(ctx, c.Text())
+                }
+            }
+            resp, err := otelserver.InstrumentPrompt(ctx, "simpleGreeting", "gemini-2.0-flash", func(ctx context.Context) (*ai.ModelResponse, error) {
+                return simpleGreetingPrompt.Execute(ctx,
+                    ai.WithInput(input),
+                    ai.WithStreaming(callback),
+                )
+            })
+            if err != nil {
+                return "", err
+            }
+            return resp.Text(), nil
+        },
+        ratelimit.WithLimit(ratelimit.Limit{Rate: 5, Burst: 10}),
+        ratelimit.WithMaxInFlight(3),
+        ratelimit.WithCircuitBreaker(modelBreaker),
+    )))
 
     // 定义 greetingWithHistory Prompt 和 Flow
     greetingWithHistoryPrompt, err := genkit.DefinePrompt(g, "greetingWithHistory",
@@ -155,63 +175,79 @@ func main() {
         log.Fatal().Msg(err.Error())
     }
 
-    greetingWithHistoryFlow := genkit.DefineFlow(g, "greetingWithHistory", func(ctx context.Context, input *customerTimeAndHistoryInput) (string, error) {
-        // 提取 HTTP header
-        headers := GetHeaders(ctx)
-        if headers != nil {
-            log.Info().
-                Str("authorization", headers.Get("Authorization")).
-                Str("x-request-id", headers.Get("X-Request-ID")).
-                Msg("Received HTTP headers in greetingWithHistory")
-        }
+    // greetingWithHistory 不再要求调用方在每次请求里手动传
+    // previousOrder：session.DefineChatFlow 会先从会话中加载并裁剪历史
+    // 消息，再交给下面的处理函数；CustomerName/PreviousOrder 则来自
+    // session.State，由 prompt 模板直接引用。
+    greetingWithHistoryFlow := session.DefineChatFlow(g, "greetingWithHistory",
+        func(ctx context.Context, input *greetingWithHistoryInput, history []*ai.Message) (*ai.ModelResponse, []*ai.Message, error) {
+            // 解析已认证的调用者（由 auth.Middleware 注入）
+            if p, ok := auth.FromContext(ctx); ok {
+                log.Info().
+                    Str("principal", p.ID).
+                    Str("auth_method", p.Method).
+                    Msg("Received authenticated request in greetingWithHistory")
+            }
 
-        // 验证 header
-        if headers != nil && !strings.HasPrefix(headers.Get("Authorization"), "Bearer ") {
-            return "", fmt.Errorf("invalid authorization header")
-        }
+            state, err := session.State(ctx)
+            if err != nil {
+                return nil, nil, fmt.Errorf("session.State: %w", err)
+            }
+            customerName, _ := state["customerName"].(string)
+            previousOrder, _ := state["previousOrder"].(string)
 
-        // 日志输入
-        inputJSON, err := json.Marshal(input)
-        if err != nil {
-            return "", fmt.Errorf("json.Marshal: %w", err)
-        }
-        log.Info().Msgf("input--------%s", string(inputJSON))
+            promptInput := &customerTimeAndHistoryInput{
+                CustomerName:  customerName,
+                CurrentTime:   input.CurrentTime,
+                PreviousOrder: previousOrder,
+            }
 
-        // 执行 prompt
-        resp, err := greetingWithHistoryPrompt.Execute(ctx,
-            ai.WithInput(input),
-        )
-        if err != nil {
-            return "", err
-        }
-        return resp.Text(), nil
-    })
+            // 日志输入
+            inputJSON, err := json.Marshal(promptInput)
+            if err != nil {
+                return nil, nil, fmt.Errorf("json.Marshal: %w", err)
+            }
+            log.Info().Msgf("input--------%s", string(inputJSON))
+
+            // session.DefineChatFlow doesn't go through ratelimit.Wrap
+            // (its handler signature carries history, which Wrap
+            // doesn't know about), so guard the model call against
+            // modelBreaker by hand to keep the same protection the
+            // other flows get.
+            if !modelBreaker.Allow() {
+                return nil, nil, ratelimit.ErrCircuitOpen
+            }
+
+            // 执行 prompt，把裁剪后的会话历史通过 ai.WithMessages 带上
+            resp, err := otelserver.InstrumentPrompt(ctx, "greetingWithHistory", "gemini-2.0-flash", func(ctx context.Context) (*ai.ModelResponse, error) {
+                return greetingWithHistoryPrompt.Execute(ctx,
+                    ai.WithInput(promptInput),
+                    ai.WithMessages(history...),
+                )
+            })
+            modelBreaker.Record(err)
+            if err != nil {
+                return nil, nil, err
+            }
+            // 只记录模型这一轮的回复：这个示例 flow 本身没有自由文本的
+            // 用户消息可记，真正的多轮对话 flow 通常还会把用户那一侧
+            // 的消息一并传给 session.Append。
+            return resp, []*ai.Message{ai.NewModelTextMessage(resp.Text())}, nil
+        },
+        session.WithTrimmer(session.SlidingWindow(20)),
+    )
 
     // 定义 testAllCoffeeFlows Flow
-    coffeeFlow := genkit.DefineFlow(g, "testAllCoffeeFlows", func(ctx context.Context, _ struct{}) (*testAllCoffeeFlowsOutput, error) {
-        // 提取 HTTP header
-        headers := GetHeaders(ctx)
-        if headers != nil {
+    coffeeFlow := genkit.DefineFlow(g, "testAllCoffeeFlows", otelserver.InstrumentFlow("testAllCoffeeFlows", "", func(ctx context.Context, _ struct{}) (*testAllCoffeeFlowsOutput, error) {
+        // 解析已认证的调用者（由 auth.Middleware 注入，并已通过
+        // WithFlowPolicy(auth.RequireScope("coffee:test")) 校验）
+        if p, ok := auth.FromContext(ctx); ok {
             log.Info().
-                Str("authorization", headers.Get("Authorization")).
-                Str("x-request-id", headers.Get("X-Request-ID")).
-                Msg("Received HTTP headers in testAllCoffeeFlows")
+                Str("principal", p.ID).
+                Str("auth_method", p.Method).
+                Msg("Received authenticated request in testAllCoffeeFlows")
         }
 
-
-
-        // 验证 header
-        if headers != nil && !strings.HasPrefix(headers.Get("Authorization"), "Bearer ") {
-            return &testAllCoffeeFlowsOutput{
-                Pass:  false,
-                Error: "invalid authorization header",
-            }, nil
-        }
-
-
-        headersAsJson, _ :=json.Marshal(headers)
-        fmt.Println("headers-------------   ",string(headersAsJson))
-
         // 运行 simpleGreetingFlow
         test1, err := simpleGreetingFlow.Run(ctx, &simpleGreetingInput{
             CustomerName: "Sam",
@@ -223,11 +259,20 @@ func main() {
             }, nil
         }
 
-        // 运行 greetingWithHistoryFlow
-        test2, err := greetingWithHistoryFlow.Run(ctx, &customerTimeAndHistoryInput{
-            CustomerName:  "Sam",
-            CurrentTime:   "09:45am",
-            PreviousOrder: "Caramel Macchiato",
+        // 运行 greetingWithHistoryFlow：CustomerName/PreviousOrder
+        // 现在通过会话状态传入，而不是每次请求都手动带上
+        testSessionCtx := session.WithSession(ctx, chatStore, "testAllCoffeeFlows-session")
+        if err := session.SetState(testSessionCtx, map[string]any{
+            "customerName":  "Sam",
+            "previousOrder": "Caramel Macchiato",
+        }); err != nil {
+            return &testAllCoffeeFlowsOutput{
+                Pass:  false,
+                Error: err.Error(),
+            }, nil
+        }
+        test2, err := greetingWithHistoryFlow.Run(testSessionCtx, &greetingWithHistoryInput{
+            CurrentTime: "09:45am",
         })
         if err != nil {
             return &testAllCoffeeFlowsOutput{
@@ -243,54 +288,88 @@ func main() {
                 test2,
             },
         }, nil
-    })
+    }))
+
+    // 用 router 自动挂载 Flow：方法/路径来自 router.WithRoute（缺省时为
+    // POST /<flow name>），请求体/响应体的编解码复用 contentneg，
+    // 并在 /openapi.json、/docs 暴露生成的 OpenAPI 3.1 文档。
+    apiRouter := router.New("", router.DocInfo{Title: "Coffee Shop Flows", Version: "1.0.0"})
+    router.Mount(apiRouter, "testAllCoffeeFlows", coffeeFlow, router.WithRoute("POST", "/testAllCoffeeFlows"))
+    router.Mount(apiRouter, "greetingWithHistory", greetingWithHistoryFlow)
 
-    // 自定义 ServeMux 和 Handler
+    // 自定义 ServeMux：simpleGreeting 需要手写 handler 以支持 SSE，
+    // 其余路由（含 /openapi.json、/docs）交给 apiRouter。
     mux := http.NewServeMux()
-    mux.HandleFunc("POST /testAllCoffeeFlows", func(w http.ResponseWriter, r *http.Request) {
-        // 验证方法
-        if r.Method != http.MethodPost {
-            http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-            return
-        }
+    mux.Handle("/", apiRouter.Handler())
 
-        // 解析请求 body（允许空 JSON）
-        var input struct{}
+    // simpleGreeting 同时支持 SSE（text/event-stream）和一次性响应，
+    // 取决于调用方的 Accept header。
+    mux.HandleFunc("POST /simpleGreeting", func(w http.ResponseWriter, r *http.Request) {
+        var input simpleGreetingInput
         if r.ContentLength > 0 {
-            if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-                log.Error().Err(err).Msg("Failed to decode request body")
+            if err := contentneg.DecodeRequest(r, &input); err != nil {
                 http.Error(w, "Invalid input", http.StatusBadRequest)
                 return
             }
         }
 
-         headers, _ :=json.Marshal(r.Header)
-
-         fmt.Println("headers-------------   ",string(headers))
-
-
-        // 注入 header
-        ctx := WithHeaders(r.Context(), r.Header)
-
-        f := coffeeFlow
+        if contentneg.IsSSERequested(r) {
+            stream, err := contentneg.NewSSEWriter(w, contentneg.JSONCodec{})
+            if err != nil {
+                http.Error(w, err.Error(), http.StatusInternalServerError)
+                return
+            }
+            for result, err := range simpleGreetingFlow.Stream(r.Context(), &input) {
+                if err != nil {
+                    log.Error().Err(err).Msg("simpleGreeting streaming failed")
+                    break
+                }
+                if result.Done {
+                    _ = stream.Send(map[string]string{"final": result.Output})
+                    break
+                }
+                _ = stream.Send(map[string]string{"chunk": result.Stream})
+            }
+            _ = stream.Close()
+            return
+        }
 
-        // 调用 Flow
-        output, err := f.Run(ctx, input)
+        reply, err := simpleGreetingFlow.Run(r.Context(), &input)
         if err != nil {
-            log.Error().Err(err).Msg("Flow execution failed")
             http.Error(w, fmt.Sprintf("Flow error: %v", err), http.StatusInternalServerError)
             return
         }
-
-        // 返回响应
-        w.Header().Set("Content-Type", "application/json")
-        w.WriteHeader(http.StatusOK)
-        if err := json.NewEncoder(w).Encode(output); err != nil {
+        if err := contentneg.EncodeResponse(w, r, map[string]string{"reply": reply}); err != nil {
             log.Error().Err(err).Msg("Failed to encode response")
         }
     })
 
-    if err := server.Start(ctx, "127.0.0.1:8000", mux);err != nil{
+    // 用 session.Middleware 从请求的 X-Session-Id header（或回退到
+    // genkit_session cookie）解析会话 ID，让 greetingWithHistory 能
+    // 通过 session.Load/session.State 找到这位客户的历史和状态。
+    sessionedMux := session.Middleware(mux, chatStore)
+
+    // 用 auth.Middleware 包装整个 mux：所有请求先认证，
+    // testAllCoffeeFlows 额外要求 "coffee:test" scope。
+    authenticators := []auth.Authenticator{auth.NewStaticBearerAuthenticator(staticTokens)}
+    authedMux := auth.Middleware(sessionedMux, authenticators,
+        auth.WithFlowPolicy("testAllCoffeeFlows", auth.RequireScope("coffee:test")),
+    )
+
+    // /openapi.json 和 /docs 不需要认证：它们描述的是 API 本身，而不是
+    // 某次 flow 调用，挡在 auth.Middleware 后面会让“自描述 API”失去意义。
+    topMux := http.NewServeMux()
+    topMux.Handle("GET /openapi.json", apiRouter.Handler())
+    topMux.Handle("GET /docs", apiRouter.Handler())
+    topMux.Handle("/", authedMux)
+
+    // 配置 OTel：追踪 span，Prometheus 指标走 promauto 全局默认注册表，
+    // 同样挂载到这个 mux 的 /metrics 上。
+    tp := sdktrace.NewTracerProvider()
+    defer tp.Shutdown(ctx)
+    instrumentedMux := otelserver.WithOTel(tp)(topMux)
+
+    if err := server.Start(ctx, "127.0.0.1:8000", instrumentedMux); err != nil {
     // 启动服务器
     log.Fatal().Msg(err.Error())
     }